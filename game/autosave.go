@@ -0,0 +1,208 @@
+package game
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAutosaveInterval is how often the game is saved automatically
+	DefaultAutosaveInterval = 5 * time.Minute
+	// DefaultAutosaveSlots is how many rotating autosaves are kept
+	DefaultAutosaveSlots = 3
+
+	// autosavePrefix names the autosave slots: autosave-1 is the newest
+	autosavePrefix = "autosave-"
+	// sessionLockFile exists in the save directory while a game is running;
+	// finding it at startup means the previous run did not exit cleanly
+	sessionLockFile = "session.lock"
+)
+
+// autosaveName returns the save name of an autosave slot (1 is the newest)
+func autosaveName(slot int) string {
+	return autosavePrefix + strconv.Itoa(slot)
+}
+
+// LatestAutosave is the save name of the most recent autosave
+const LatestAutosave = autosavePrefix + "1"
+
+// RecoverySave is the save name under which the last autosave of a session
+// that didn't exit cleanly is kept, out of reach of the autosave rotation
+const RecoverySave = "recovered-session"
+
+// Autosave saves the game into the newest autosave slot
+func (ge *GameEngine) Autosave() error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.autosave()
+}
+
+// autosave shifts the older autosaves down one slot, dropping the oldest,
+// and writes the current game to the newest slot. The caller must hold the
+// engine lock.
+func (ge *GameEngine) autosave() error {
+	slots := ge.AutosaveSlots
+	if slots < 1 {
+		slots = 1
+	}
+
+	dir := SaveDir()
+	for slot := slots - 1; slot >= 1; slot-- {
+		from := filepath.Join(dir, autosaveName(slot)+".json")
+		to := filepath.Join(dir, autosaveName(slot+1)+".json")
+		if err := os.Rename(from, to); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate autosaves: %w", err)
+		}
+	}
+
+	return ge.saveGame(LatestAutosave)
+}
+
+// autosaveLoop saves the game every AutosaveInterval until stopped
+func (ge *GameEngine) autosaveLoop() {
+	if ge.AutosaveInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(ge.AutosaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ge.mu.Lock()
+			if err := ge.autosave(); err != nil {
+				ge.Display.ShowMessage("Autosave failed: "+err.Error(), "error")
+			}
+			ge.mu.Unlock()
+		case <-ge.stopAutosave:
+			return
+		}
+	}
+}
+
+// Shutdown autosaves the game and marks the session as cleanly ended.
+// It is safe to call more than once.
+func (ge *GameEngine) Shutdown() error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.shutdown()
+}
+
+// shutdown is Shutdown for callers that already hold the engine lock
+func (ge *GameEngine) shutdown() error {
+	if !ge.sessionActive {
+		return nil
+	}
+
+	err := ge.autosave()
+	ge.endSession()
+	return err
+}
+
+// beginSession marks a game as running by creating the session lock file,
+// which holds the process ID and when the session started
+func (ge *GameEngine) beginSession() {
+	dir := SaveDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	content := []byte(strconv.Itoa(os.Getpid()) + "\n" + time.Now().Format(time.RFC3339Nano) + "\n")
+	if err := os.WriteFile(filepath.Join(dir, sessionLockFile), content, 0644); err == nil {
+		ge.sessionActive = true
+	}
+}
+
+// endSession removes the session lock file after a clean exit
+func (ge *GameEngine) endSession() {
+	os.Remove(filepath.Join(SaveDir(), sessionLockFile))
+	ge.sessionActive = false
+}
+
+// PreserveCrashedSession reports whether the previous run ended without a
+// clean shutdown and left an autosave of its own behind to recover from. An
+// autosave written before that session started belongs to an older game and
+// isn't offered. The autosave is copied to RecoverySave, so the new session's
+// autosaves can't rotate it away before the player chooses to recover it.
+// Call it before starting the engine, which replaces the session lock file.
+func PreserveCrashedSession() bool {
+	dir := SaveDir()
+	started, err := sessionStartTime(filepath.Join(dir, sessionLockFile))
+	if err != nil {
+		return false
+	}
+
+	autosavePath := filepath.Join(dir, LatestAutosave+".json")
+	header, err := ReadSaveHeader(autosavePath)
+	if err != nil || header.SavedAt.Before(started) {
+		return false
+	}
+	data, err := os.ReadFile(autosavePath)
+	if err != nil {
+		return false
+	}
+	return writeFileAtomic(filepath.Join(dir, RecoverySave+".json"), data) == nil
+}
+
+// sessionStartTime reads when a session started from its lock file. Lock
+// files from before the start time was recorded fall back to their
+// modification time.
+func sessionStartTime(lockPath string) (time.Time, error) {
+	content, err := os.ReadFile(lockPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) >= 2 {
+		if started, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(lines[1])); err == nil {
+			return started, nil
+		}
+	}
+
+	info, err := os.Stat(lockPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// writeFileAtomic writes data to a temporary file in the target's directory
+// and renames it into place, so a crash mid-write never leaves a truncated file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
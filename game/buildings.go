@@ -1,58 +1,77 @@
 package game
 
+// ConstructionTask is the villager task that works on buildings under construction
+const ConstructionTask = "construction"
+
+// ConstructionRefundRate is the share of a building's cost returned when its
+// construction is cancelled
+const ConstructionRefundRate = 0.5
+
 // BuildingManager handles building construction and effects
 type BuildingManager struct {
 	buildings           map[string]int
 	buildingCosts       map[string]map[string]float64
 	buildingEffects     map[string]map[string]float64
 	buildingRateBonuses map[string]map[string]map[string]float64 // building -> villagerType -> resource -> bonus percentage
+	buildTimes          map[string]int
+	upgrades            map[string]string   // building -> the tier it can be upgraded to
+	upgradeFrom         map[string]string   // tier -> the building it is upgraded from
+	construction        []*ConstructionSite // Buildings under construction, worked on in order
+	nextSiteID          int
+	modifiers           *ResearchModifiers
 }
 
-// NewBuildingManager creates a new building manager
-func NewBuildingManager() *BuildingManager {
+// ConstructionSite is a building that has been paid for and is being built
+type ConstructionSite struct {
+	ID       int                `json:"id"`
+	Building string             `json:"building"`
+	Work     float64            `json:"work"`               // Builder-ticks of work done so far
+	Required float64            `json:"required"`           // Builder-ticks of work needed
+	Paid     map[string]float64 `json:"paid"`               // Resources spent, for refunds
+	Upgrades string             `json:"upgrades,omitempty"` // Building replaced when an upgrade is finished
+}
+
+// Progress returns the fraction of the work done, from 0 to 1
+func (s *ConstructionSite) Progress() float64 {
+	if s.Required <= 0 {
+		return 1
+	}
+	return min(s.Work/s.Required, 1)
+}
+
+// Label names what is being built, e.g. "farm" or "hut → house" for an upgrade
+func (s *ConstructionSite) Label() string {
+	if s.Upgrades != "" {
+		return s.Upgrades + " → " + s.Building
+	}
+	return s.Building
+}
+
+// NewBuildingManager creates a new building manager from the content pack
+func NewBuildingManager(content *ContentPack) *BuildingManager {
 	bm := &BuildingManager{
-		buildings: map[string]int{
-			"hut":         0,
-			"farm":        0,
-			"lumber_mill": 0,
-			"mine":        0,
-			"market":      0,
-			"library":     0,
-		},
-		buildingCosts: map[string]map[string]float64{
-			"hut":         {"wood": 20},
-			"farm":        {"wood": 100, "stone": 50, "food": 100},
-			"lumber_mill": {"wood": 100, "stone": 300},
-			"mine":        {"wood": 100, "stone": 400},
-			"market":      {"wood": 200, "stone": 200, "gold": 100},
-			"library":     {"wood": 400, "stone": 200, "knowledge": 100},
-		},
-		buildingEffects: map[string]map[string]float64{
-			"hut":         {"villager_capacity": 2},
-			"farm":        {"food": 3.5}, // Increased from 2 to improve food production
-			"lumber_mill": {"wood": 2},
-			"mine":        {"stone": 1, "gold": 0.2},
-			"market":      {"gold": 0.5},
-			"library":     {"knowledge": 0.5},
-		},
-		buildingRateBonuses: map[string]map[string]map[string]float64{
-			"farm": {
-				"villager": {"food": 0.08}, // Increased from 0.05 to improve food gathering efficiency
-			},
-			"lumber_mill": {
-				"villager": {"wood": 0.1}, // Villagers get +10% wood gathering rate per lumber mill
-			},
-			"mine": {
-				"villager": {"stone": 0.05, "gold": 0.05}, // Villagers get +5% stone and gold gathering rate per mine
-			},
-			"market": {
-				"villager": {"gold": 0.1}, // Villagers get +10% gold gathering rate per market
-			},
-			"library": {
-				"scholar":  {"knowledge": 0.15}, // Scholars get +15% knowledge gathering rate per library
-				"villager": {"knowledge": 0.02}, // Villagers get +2% knowledge gathering rate per library
-			},
-		},
+		buildings:           make(map[string]int),
+		buildingCosts:       make(map[string]map[string]float64),
+		buildingEffects:     make(map[string]map[string]float64),
+		buildingRateBonuses: make(map[string]map[string]map[string]float64),
+		buildTimes:          make(map[string]int),
+		upgrades:            make(map[string]string),
+		upgradeFrom:         make(map[string]string),
+		nextSiteID:          1,
+		modifiers:           NewResearchModifiers(),
+	}
+	for name, def := range content.Buildings {
+		bm.buildings[name] = 0
+		bm.buildingCosts[name] = def.Cost
+		bm.buildingEffects[name] = def.Effects
+		bm.buildTimes[name] = def.BuildTime
+		if def.UpgradeFrom != "" {
+			bm.upgrades[def.UpgradeFrom] = name
+			bm.upgradeFrom[name] = def.UpgradeFrom
+		}
+		if def.RateBonuses != nil {
+			bm.buildingRateBonuses[name] = def.RateBonuses
+		}
 	}
 	return bm
 }
@@ -80,17 +99,44 @@ func (bm *BuildingManager) GetCount(building string) int {
 	return bm.buildings[building]
 }
 
-// GetAll returns all buildings and their counts
+// CountWithUpgrades returns the count of a building plus every building
+// upgraded from it, so upgrading doesn't undo progress towards an age
+func (bm *BuildingManager) CountWithUpgrades(building string) int {
+	count := 0
+	for seen := map[string]bool{}; building != "" && !seen[building]; building = bm.upgrades[building] {
+		seen[building] = true
+		count += bm.buildings[building]
+	}
+	return count
+}
+
+// GetAll returns a copy of all buildings and their counts
 func (bm *BuildingManager) GetAll() map[string]int {
-	return bm.buildings
+	buildings := make(map[string]int, len(bm.buildings))
+	for building, count := range bm.buildings {
+		buildings[building] = count
+	}
+	return buildings
+}
+
+// ApplyResearch sets the research modifiers used for costs, production and capacity
+func (bm *BuildingManager) ApplyResearch(modifiers *ResearchModifiers) {
+	bm.modifiers = modifiers
 }
 
-// GetCost returns the cost to build a specific building
+// GetCost returns the cost to build a specific building, after research cost reductions
 func (bm *BuildingManager) GetCost(building string) map[string]float64 {
-	if cost, exists := bm.buildingCosts[building]; exists {
-		return cost
+	baseCost, exists := bm.buildingCosts[building]
+	if !exists {
+		return nil
 	}
-	return nil
+
+	multiplier := bm.modifiers.CostMultiplier(building)
+	cost := make(map[string]float64, len(baseCost))
+	for resource, amount := range baseCost {
+		cost[resource] = amount * multiplier
+	}
+	return cost
 }
 
 // GetEffect returns the effect of a specific building
@@ -103,8 +149,8 @@ func (bm *BuildingManager) GetEffect(building string) map[string]float64 {
 
 // CanBuild checks if we can build a specific building
 func (bm *BuildingManager) CanBuild(building string, resources *ResourceManager) bool {
-	costs, exists := bm.buildingCosts[building]
-	if !exists {
+	costs := bm.GetCost(building)
+	if costs == nil {
 		return false
 	}
 
@@ -118,31 +164,169 @@ func (bm *BuildingManager) CanBuild(building string, resources *ResourceManager)
 	return true
 }
 
-// Build builds a new building
-func (bm *BuildingManager) Build(building string, resources *ResourceManager) bool {
+// Build pays for a new building and starts constructing it. Buildings
+// without a build time are finished at once, and no construction site is
+// returned for them.
+func (bm *BuildingManager) Build(building string, resources *ResourceManager) (*ConstructionSite, bool) {
 	if !bm.CanBuild(building, resources) {
-		return false
+		return nil, false
 	}
+	return bm.start(building, "", resources), true
+}
+
+// GetUpgrade returns the tier a building can be upgraded to, or ""
+func (bm *BuildingManager) GetUpgrade(building string) string {
+	return bm.upgrades[building]
+}
 
-	// Spend resources
-	for resource, amount := range bm.buildingCosts[building] {
+// GetUpgradeBase returns the building a tier is upgraded from, or "" for
+// buildings that are built directly
+func (bm *BuildingManager) GetUpgradeBase(building string) string {
+	return bm.upgradeFrom[building]
+}
+
+// GetUpgradable returns how many of a building can still be upgraded: those
+// not already being upgraded
+func (bm *BuildingManager) GetUpgradable(building string) int {
+	count := bm.buildings[building]
+	for _, site := range bm.construction {
+		if site.Upgrades == building {
+			count--
+		}
+	}
+	return max(count, 0)
+}
+
+// Upgrade pays for upgrading one building to its next tier and starts the
+// work. The building keeps working until the upgrade is finished and is then
+// replaced; upgrades without a build time are finished at once, and no
+// construction site is returned for them.
+func (bm *BuildingManager) Upgrade(building string, resources *ResourceManager) (*ConstructionSite, bool) {
+	tier := bm.upgrades[building]
+	if tier == "" || bm.GetUpgradable(building) == 0 || !bm.CanBuild(tier, resources) {
+		return nil, false
+	}
+	return bm.start(tier, building, resources), true
+}
+
+// start spends a building's cost and either finishes it at once or puts it
+// under construction. A building being upgraded is named by replaces.
+func (bm *BuildingManager) start(building, replaces string, resources *ResourceManager) *ConstructionSite {
+	cost := bm.GetCost(building)
+	for resource, amount := range cost {
 		resources.Remove(resource, amount)
 	}
 
-	// Add the building
-	bm.Add(building, 1)
-	return true
+	site := &ConstructionSite{
+		ID:       bm.nextSiteID,
+		Building: building,
+		Required: float64(bm.buildTimes[building]),
+		Paid:     cost,
+		Upgrades: replaces,
+	}
+	if site.Required <= 0 {
+		bm.finish(site)
+		return nil
+	}
+	bm.nextSiteID++
+	bm.construction = append(bm.construction, site)
+	return site
+}
+
+// finish adds a finished building, replacing the one it upgrades
+func (bm *BuildingManager) finish(site *ConstructionSite) {
+	if site.Upgrades != "" {
+		bm.Remove(site.Upgrades, 1)
+	}
+	bm.Add(site.Building, 1)
+}
+
+// GetBuildTime returns the builder-ticks of work needed to construct a building
+func (bm *BuildingManager) GetBuildTime(building string) int {
+	return bm.buildTimes[building]
+}
+
+// Construct puts the given builder-ticks of work into the buildings under
+// construction, oldest first, and returns the sites it finished
+func (bm *BuildingManager) Construct(work float64) []ConstructionSite {
+	finished := []ConstructionSite{}
+	for len(bm.construction) > 0 && work > 0 {
+		site := bm.construction[0]
+		spent := min(work, site.Required-site.Work)
+		site.Work += spent
+		work -= spent
+		if site.Work < site.Required {
+			break
+		}
+
+		bm.construction = bm.construction[1:]
+		bm.finish(site)
+		finished = append(finished, *site)
+	}
+	return finished
+}
+
+// CancelConstruction abandons a building under construction, returning
+// ConstructionRefundRate of what was paid for it. A building being upgraded
+// simply stays as it was. It returns the site and the refund.
+func (bm *BuildingManager) CancelConstruction(id int, resources *ResourceManager) (*ConstructionSite, map[string]float64, bool) {
+	for i, site := range bm.construction {
+		if site.ID != id {
+			continue
+		}
+
+		bm.construction = append(bm.construction[:i], bm.construction[i+1:]...)
+		refund := make(map[string]float64, len(site.Paid))
+		for resource, amount := range site.Paid {
+			refund[resource] = amount * ConstructionRefundRate
+			resources.Add(resource, refund[resource])
+		}
+		return site, refund, true
+	}
+	return nil, nil, false
+}
+
+// GetConstruction returns copies of the buildings under construction, in the
+// order they are worked on
+func (bm *BuildingManager) GetConstruction() []ConstructionSite {
+	sites := make([]ConstructionSite, 0, len(bm.construction))
+	for _, site := range bm.construction {
+		sites = append(sites, *site)
+	}
+	return sites
+}
+
+// RestoreConstruction replaces the buildings under construction with saved
+// ones. Sites for buildings that are no longer defined, or upgrades the
+// content pack no longer has, are dropped and returned.
+func (bm *BuildingManager) RestoreConstruction(sites []ConstructionSite) []string {
+	var unknown []string
+	bm.construction = nil
+	bm.nextSiteID = 1
+	for _, s := range sites {
+		if _, exists := bm.buildings[s.Building]; !exists || (s.Upgrades != "" && bm.upgrades[s.Upgrades] != s.Building) {
+			unknown = append(unknown, s.Building)
+			continue
+		}
+		site := s
+		bm.construction = append(bm.construction, &site)
+		if s.ID >= bm.nextSiteID {
+			bm.nextSiteID = s.ID + 1
+		}
+	}
+	return unknown
 }
 
-// Update updates resources based on building effects
-func (bm *BuildingManager) Update(resources *ResourceManager) {
+// Update updates resources based on building effects over the given number of ticks
+func (bm *BuildingManager) Update(resources *ResourceManager, ticks int) {
 	for building, count := range bm.buildings {
 		if count > 0 {
 			if effects, exists := bm.buildingEffects[building]; exists {
 				for resource, amount := range effects {
 					if resource != "villager_capacity" {
 						// Only add direct resource production here, not collection rate bonuses
-						resources.Add(resource, amount*float64(count))
+						production := amount * float64(count) * float64(ticks)
+						resources.Add(resource, production*bm.modifiers.ProductionMultiplier(resource))
 					}
 				}
 			}
@@ -152,7 +336,7 @@ func (bm *BuildingManager) Update(resources *ResourceManager) {
 
 // GetVillagerCapacity calculates total villager capacity from buildings
 func (bm *BuildingManager) GetVillagerCapacity() int {
-	capacity := 1 // Start with capacity for 1 villager
+	capacity := 1 + bm.modifiers.CapacityBonus // Start with capacity for 1 villager plus research
 
 	for building, count := range bm.buildings {
 		if count > 0 {
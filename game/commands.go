@@ -1,6 +1,9 @@
 package game
 
 import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -8,143 +11,427 @@ import (
 // CommandHandler processes user commands
 type CommandHandler struct {
 	Game     *GameEngine
-	Commands map[string]string
+	Registry *CommandRegistry
 }
 
-// NewCommandHandler creates a new command handler
+// NewCommandHandler creates a new command handler with the built-in commands
 func NewCommandHandler(gameEngine *GameEngine) *CommandHandler {
 	ch := &CommandHandler{
-		Game: gameEngine,
-		Commands: map[string]string{
-			"help":      "Display available commands",
-			"gather":    "Assign villagers to gather resources (gather <resource> <count>)",
-			"build":     "Build a structure (build <building>)",
-			"status":    "Show detailed status of your civilization",
-			"assign":    "Assign villagers to tasks (assign <villager_type> <resource> <count>)",
-			"unassign":  "Unassign villagers from tasks (unassign <villager_type> <resource> <count>)",
-			"recruit":   "Recruit new villagers (recruit <villager_type> <count>)",
-			"buildings": "List available buildings and their costs",
-			"research":  "Start researching a technology (research <technology>)",
-			"techs":     "List available technologies for research",
-			"save":      "Save the current game (save <filename>)",
-			"load":      "Load a saved game (load <filename>)",
-			"saves":     "List all saved games",
-			"stats":     "Display game statistics",
-			"clear":     "Clear the console screen",
-			"quit":      "Exit the game",
-		},
+		Game:     gameEngine,
+		Registry: NewCommandRegistry(),
+	}
+	for _, cmd := range builtinCommands() {
+		if err := ch.Registry.Register(cmd); err != nil {
+			panic(err)
+		}
 	}
 	return ch
 }
 
-// GetCommandList returns the list of available commands
-func (ch *CommandHandler) GetCommandList() []string {
-	commands := make([]string, 0, len(ch.Commands))
-	for cmd := range ch.Commands {
-		commands = append(commands, cmd)
+// Register adds a command to the console, e.g. from a plugin
+func (ch *CommandHandler) Register(cmd *Command) error {
+	return ch.Registry.Register(cmd)
+}
+
+// builtinCommands returns the commands that ship with the game
+func builtinCommands() []*Command {
+	count := ArgSpec{Name: "count", Kind: ArgCount, Description: "how many villagers"}
+
+	return []*Command{
+		{
+			Name:    "help",
+			Aliases: []string{"?"},
+			Args:    []ArgSpec{{Name: "command", Optional: true, Complete: completeCommands, Description: "command to explain"}},
+			Summary: "Display available commands",
+			Help:    "Without arguments, lists every command. With a command name, shows its usage, aliases and arguments.",
+			Handler: (*CommandHandler).CmdHelp,
+		},
+		{
+			Name:    "gather",
+			Args:    []ArgSpec{{Name: "resource", Complete: completeGatherTasks, Description: "resource to gather"}, count},
+			Summary: "Assign villagers to gather resources",
+			Help:    "Assigns idle basic villagers to gather a resource. Use 'assign' for other villager types.",
+			Handler: (*CommandHandler).CmdGather,
+		},
+		{
+			Name:    "build",
+			Args:    []ArgSpec{{Name: "building", Complete: completeBuildings, Description: "building to construct"}},
+			Summary: "Build a structure",
+			Help:    "Builds a structure if it is available in the current age (or unlocked by research) and you can pay its cost. Use 'buildings' to see costs.",
+			Handler: (*CommandHandler).CmdBuild,
+		},
+		{
+			Name:    "status",
+			Summary: "Show detailed status of your civilization",
+			Handler: (*CommandHandler).CmdStatus,
+		},
+		{
+			Name: "assign",
+			Args: []ArgSpec{
+				{Name: "villager_type", Complete: completeVillagers, Description: "type of villager, e.g. villager or scholar"},
+				{Name: "resource", Complete: completeTasks, Description: "resource to work on"},
+				count,
+			},
+			Summary: "Assign villagers to tasks",
+			Help:    "Moves idle villagers of a type to work on a resource. Each villager type can only work on certain resources.",
+			Handler: (*CommandHandler).CmdAssign,
+		},
+		{
+			Name: "unassign",
+			Args: []ArgSpec{
+				{Name: "villager_type", Complete: completeVillagers, Description: "type of villager"},
+				{Name: "resource", Complete: completeTasks, Description: "resource they are working on"},
+				count,
+			},
+			Summary: "Unassign villagers from tasks",
+			Help:    "Returns villagers working on a resource to idle.",
+			Handler: (*CommandHandler).CmdUnassign,
+		},
+		{
+			Name:    "recruit",
+			Args:    []ArgSpec{{Name: "villager_type", Complete: completeVillagers, Description: "type of villager to recruit"}, count},
+			Summary: "Recruit new villagers",
+			Help:    "Recruits villagers for food. You need enough housing capacity; build huts to raise it.",
+			Handler: (*CommandHandler).CmdRecruit,
+		},
+		{
+			Name:    "buildings",
+			Summary: "List available buildings and their costs",
+			Handler: (*CommandHandler).CmdBuildings,
+		},
+		{
+			Name: "research",
+			Args: []ArgSpec{
+				{Name: "technology|cancel", Complete: completeTechnologies, Description: "technology to research, or 'cancel'"},
+				{Name: "technology", Optional: true, Complete: completeQueuedTechnologies, Description: "queued technology to cancel"},
+			},
+			Summary: "Research a technology, or queue it",
+			Help: "Adds a technology to the research queue, along with any prerequisites it still needs, which are researched first. " +
+				"Technologies are researched one at a time, paid for with knowledge over time; the next one starts when the " +
+				"current one completes. 'research cancel <technology>' removes a technology, and anything queued that needs it, from the queue.",
+			Handler: (*CommandHandler).CmdResearch,
+		},
+		{
+			Name:    "techs",
+			Aliases: []string{"technologies"},
+			Summary: "List available technologies for research",
+			Handler: (*CommandHandler).CmdTechs,
+		},
+		{
+			Name:    "save",
+			Args:    []ArgSpec{{Name: "filename", Complete: completeSaves, Description: "name of the save"}},
+			Summary: "Save the current game",
+			Help:    "Writes the game to the save directory, replacing any save with the same name.",
+			Handler: (*CommandHandler).CmdSave,
+		},
+		{
+			Name: "load",
+			Args: []ArgSpec{
+				{Name: "filename", Complete: completeSaves, Description: "name of the save"},
+				{Name: "unverified", Kind: ArgChoice, Optional: true, Choices: []string{"unverified"},
+					Description: "load even if the save failed its integrity check"},
+			},
+			Summary: "Load a saved game",
+			Help:    "Replaces the current game with a save. Saves that were modified after they were written are refused unless loaded unverified, which is recorded in the statistics.",
+			Handler: (*CommandHandler).CmdLoad,
+		},
+		{
+			Name:    "saves",
+			Summary: "List all saved games",
+			Handler: (*CommandHandler).CmdListSaves,
+		},
+		{
+			Name:    "name",
+			Args:    []ArgSpec{{Name: "civilization name", Kind: ArgRest, Optional: true, Description: "new name; may contain spaces"}},
+			Summary: "Name your civilization",
+			Help:    "Shows or changes the name of your civilization. The name is shown when browsing saves.",
+			Handler: (*CommandHandler).CmdName,
+		},
+		{
+			Name:    "export",
+			Args:    []ArgSpec{{Name: "save", Complete: completeSaves, Description: "name of the save to export"}},
+			Summary: "Export a save as a shareable string and .civsave file",
+			Help:    "Writes <save>.civsave to the save directory and prints the same save as a text string that can be pasted elsewhere.",
+			Handler: (*CommandHandler).CmdExport,
+		},
+		{
+			Name: "import",
+			Args: []ArgSpec{
+				{Name: "string|file", Description: "export string or .civsave file"},
+				{Name: "name", Description: "name for the imported save"},
+			},
+			Summary: "Import a shared save",
+			Help:    "Turns an export back into a save. Saves from newer versions of the game, altered saves and existing names are refused.",
+			Handler: (*CommandHandler).CmdImport,
+		},
+		{
+			Name: "diff",
+			Args: []ArgSpec{
+				{Name: "saveA", Complete: completeSaves, Description: "first save"},
+				{Name: "saveB", Complete: completeSaves, Description: "second save"},
+				{Name: "json", Kind: ArgChoice, Optional: true, Choices: []string{"json"}, Description: "print the differences as JSON"},
+			},
+			Summary: "Compare two saves",
+			Help:    "Lists the differences in resources, buildings, villagers, research and statistics, with the change from the first save to the second.",
+			Handler: (*CommandHandler).CmdDiff,
+		},
+		{
+			Name: "run",
+			Args: []ArgSpec{
+				{Name: "file|stop", Complete: completeRun, Description: "script file to run, or 'stop' to stop the running script"},
+				{Name: "stop-on-error", Kind: ArgChoice, Optional: true, Choices: []string{"stop-on-error"},
+					Description: "stop at the first command that fails"},
+			},
+			Summary: "Run a script of commands",
+			Help: "Runs the commands in a file, one per line. Anything after '#' is a comment. Scripts can also use " +
+				"'wait <ticks>', 'wait until <condition>' (e.g. 'wait until wood >= 20 and idle > 0') and 'run <file>'. " +
+				"While the game clock is running, the script runs in the background; 'run stop' stops it.",
+			Handler: (*CommandHandler).CmdRun,
+		},
+		{
+			Name: "upgrade",
+			Args: []ArgSpec{
+				{Name: "building", Complete: completeUpgrades, Description: "building to upgrade to its next tier"},
+				{Name: "count", Kind: ArgCount, Optional: true, Description: "how many to upgrade; defaults to 1"},
+			},
+			Summary: "Upgrade buildings to their next tier",
+			Help: "Pays for upgrading buildings you own to their next tier, e.g. a hut to a house, once the tier is unlocked " +
+				"by an age or research. Upgrades are constructed like new buildings; the old building keeps working until " +
+				"its upgrade is finished. Upgraded buildings still count towards age requirements.",
+			Handler: (*CommandHandler).CmdUpgrade,
+		},
+		{
+			Name:    "construction",
+			Aliases: []string{"sites"},
+			Args: []ArgSpec{
+				{Name: "cancel", Kind: ArgChoice, Optional: true, Choices: []string{"cancel"}, Description: "cancel a construction site"},
+				{Name: "site", Optional: true, Complete: completeSites, Description: "number of the site to cancel"},
+			},
+			Summary: "Show or cancel buildings under construction",
+			Help: "Buildings take builder-ticks of work to finish once paid for: villagers assigned to the '" + ConstructionTask +
+				"' task each put one tick of work per tick into the oldest site first. Without arguments, lists the sites and " +
+				"their progress. 'construction cancel <site>' abandons a site and refunds half of its cost.",
+			Handler: (*CommandHandler).CmdConstruction,
+		},
+		{
+			Name: "queue",
+			Args: []ArgSpec{
+				{Name: "build|list|cancel|reorder", Kind: ArgChoice, Optional: true,
+					Choices: []string{"build", "list", "cancel", "reorder"}, Description: "what to do; defaults to list"},
+				{Name: "args", Kind: ArgRest, Optional: true, Complete: completeQueue,
+					Description: "'build <building> [count]', 'cancel <order>' or 'reorder <order> <position>'"},
+			},
+			Summary: "Queue buildings to build once affordable",
+			Help: "'queue build <building> [count]' adds an order to the build queue. Each tick, the order at the front is " +
+				"built as soon as its cost can be paid; orders behind it wait their turn. Resources aren't set aside, so " +
+				"spending them elsewhere delays the queue. 'queue cancel <order>' removes an order and " +
+				"'queue reorder <order> <position>' moves it, e.g. 'queue reorder 3 1' to build order #3 next.",
+			Handler: (*CommandHandler).CmdQueue,
+		},
+		{
+			Name:    "rule",
+			Aliases: []string{"rules"},
+			Args: []ArgSpec{
+				{Name: "add|list|remove|enable|disable", Kind: ArgChoice, Optional: true,
+					Choices: []string{"add", "list", "remove", "enable", "disable"}, Description: "what to do; defaults to list"},
+				{Name: "rule", Kind: ArgRest, Optional: true, Complete: completeRules,
+					Description: "for add, 'when <condition> <action>'; otherwise the rule's number"},
+			},
+			Summary: "Automate actions with rules",
+			Help: "Rules carry out an action every tick their condition holds, e.g. 'rule add when wood >= 20 build hut'. " +
+				"Conditions are written as for 'wait until' in scripts. Actions are 'assign [count|all] [villager type] to <task>', " +
+				"'build <building> [count]', 'recruit <villager type> [count]', 'research <technology>' and " +
+				"'research cheapest' (or 'start cheapest available tech'). 'then' may separate the condition from the action. " +
+				"Rules are kept in saves; 'rule disable <n>' pauses one without removing it.",
+			Handler: (*CommandHandler).CmdRule,
+		},
+		{
+			Name:    "stats",
+			Aliases: []string{"statistics"},
+			Summary: "Display game statistics",
+			Handler: (*CommandHandler).CmdStats,
+		},
+		{
+			Name:    "pause",
+			Summary: "Pause the game clock",
+			Handler: (*CommandHandler).CmdPause,
+		},
+		{
+			Name:    "resume",
+			Summary: "Resume the game clock after a pause",
+			Handler: (*CommandHandler).CmdResume,
+		},
+		{
+			Name:    "speed",
+			Args:    []ArgSpec{{Name: "multiplier", Optional: true, Complete: completeSpeeds, Description: "0.5x, 1x, 2x, 5x or 10x"}},
+			Summary: "Show or change the game speed",
+			Help:    "Without arguments, shows the current speed. Otherwise sets it, e.g. 'speed 2x'.",
+			Handler: (*CommandHandler).CmdSpeed,
+		},
+		{
+			Name:    "clear",
+			Summary: "Clear the console screen",
+			Handler: func(ch *CommandHandler, args []string) error {
+				// This will be handled in the UI
+				return nil
+			},
+		},
+		{
+			Name:    "quit",
+			Aliases: []string{"exit"},
+			Summary: "Exit the game",
+			Help:    "Saves the game to an autosave slot and exits.",
+			Handler: func(ch *CommandHandler, args []string) error {
+				ch.Game.Quit()
+				return nil
+			},
+		},
 	}
-	return commands
 }
 
-// Process processes a command string
-func (ch *CommandHandler) Process(commandStr string) {
-	// Empty command
-	if commandStr == "" {
-		return
+// GetCommandList returns the names of the available commands
+func (ch *CommandHandler) GetCommandList() []string {
+	commands := ch.Registry.Commands()
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.Name)
 	}
+	return names
+}
 
+// Process processes a command string. Errors are shown to the player and
+// also returned, so scripts can stop on them.
+func (ch *CommandHandler) Process(commandStr string) error {
 	// Split the command into parts
 	parts := strings.Fields(commandStr)
+	if len(parts) == 0 {
+		return nil
+	}
 	command := strings.ToLower(parts[0])
 	args := parts[1:]
 
-	// Process command
-	switch command {
-	case "help":
-		ch.CmdHelp()
-	case "gather":
-		ch.CmdGather(args)
-	case "build":
-		ch.CmdBuild(args)
-	case "status":
-		ch.CmdStatus()
-	case "assign":
-		ch.CmdAssign(args)
-	case "unassign":
-		ch.CmdUnassign(args)
-	case "recruit":
-		ch.CmdRecruit(args)
-	case "buildings":
-		ch.CmdBuildings()
-	case "research":
-		ch.CmdResearch(args)
-	case "techs":
-		ch.CmdTechs()
-	case "save":
-		ch.CmdSave(args)
-	case "load":
-		ch.CmdLoad(args)
-	case "saves":
-		ch.CmdListSaves()
-	case "stats":
-		ch.CmdStats()
-	case "clear":
-		// This will be handled in the UI
-	case "quit":
-		ch.Game.Quit()
-	default:
-		ch.Game.Display.ShowMessage("Unknown command: "+command+". Type 'help' for available commands.", "error")
-	}
-}
-
-// CmdHelp displays help information
-func (ch *CommandHandler) CmdHelp() {
-	ch.Game.Display.ShowHelp(ch.Commands)
+	err := ch.dispatch(command, args)
+	if err != nil {
+		ch.Game.Display.ShowMessage(err.Error(), "error")
+	}
+	return err
 }
 
-// CmdGather assigns villagers to gather resources
-func (ch *CommandHandler) CmdGather(args []string) {
-	if len(args) != 2 {
-		ch.Game.Display.ShowMessage("Usage: gather <resource> <count>", "error")
-		return
+// dispatch looks up a command, checks its arguments and runs it
+func (ch *CommandHandler) dispatch(command string, args []string) error {
+	cmd, exists := ch.Registry.Lookup(command)
+	if !exists {
+		return ch.unknownCommand(command)
+	}
+	if err := cmd.CheckArgs(args); err != nil {
+		return err
 	}
+	return cmd.Handler(ch, args)
+}
 
-	resource := args[0]
-	count, err := strconv.Atoi(args[1])
-	if err != nil || count <= 0 {
-		ch.Game.Display.ShowMessage("Count must be a positive number", "error")
-		return
+// unknownCommand builds the error for a command that isn't registered,
+// suggesting the closest match
+func (ch *CommandHandler) unknownCommand(command string) error {
+	if suggestion := ch.Registry.Suggest(command); suggestion != "" {
+		return errors.New("Unknown command: " + command + ". Did you mean '" + suggestion + "'?")
+	}
+	return errors.New("Unknown command: " + command + ". Type 'help' for available commands.")
+}
+
+// CmdHelp lists the commands, or explains one command in detail
+func (ch *CommandHandler) CmdHelp(args []string) error {
+	if len(args) == 0 {
+		ch.Game.Display.ShowHelp(ch.Registry.Summaries())
+		return nil
+	}
+
+	cmd, exists := ch.Registry.Lookup(args[0])
+	if !exists {
+		return ch.unknownCommand(args[0])
+	}
+
+	ch.Game.Display.ShowMessage("=== "+cmd.Name+" ===", "highlight")
+	ch.Game.Display.ShowMessage(cmd.Summary, "info")
+	ch.Game.Display.ShowMessage("Usage: "+cmd.Usage(), "info")
+	if len(cmd.Aliases) > 0 {
+		ch.Game.Display.ShowMessage("Aliases: "+strings.Join(cmd.Aliases, ", "), "info")
+	}
+	for _, arg := range cmd.Args {
+		line := "  " + arg.Name
+		if arg.Description != "" {
+			line += " - " + arg.Description
+		}
+		if arg.Optional {
+			line += " (optional)"
+		}
+		ch.Game.Display.ShowMessage(line, "info")
+	}
+	if cmd.Help != "" {
+		ch.Game.Display.ShowMessage(cmd.Help, "info")
 	}
+	return nil
+}
+
+// CmdGather assigns villagers to gather resources
+func (ch *CommandHandler) CmdGather(args []string) error {
+	resource := args[0]
+	count, _ := strconv.Atoi(args[1])
 
 	// Try to assign villagers
-	if ch.Game.Villagers.Assign("villager", resource, count) {
-		ch.Game.Display.ShowMessage("Assigned "+strconv.Itoa(count)+" villagers to gather "+resource, "success")
-	} else {
-		ch.Game.Display.ShowMessage("Failed to assign villagers. Not enough idle villagers or invalid resource.", "error")
+	if !ch.Game.Villagers.Assign("villager", resource, count) {
+		return errors.New("Failed to assign villagers. Not enough idle villagers or invalid resource.")
 	}
+	ch.Game.Display.ShowMessage("Assigned "+strconv.Itoa(count)+" villagers to gather "+resource, "success")
+	return nil
 }
 
 // CmdBuild builds a structure
-func (ch *CommandHandler) CmdBuild(args []string) {
-	if len(args) != 1 {
-		ch.Game.Display.ShowMessage("Usage: build <building>", "error")
-		return
+func (ch *CommandHandler) CmdBuild(args []string) error {
+	building := strings.ToLower(args[0])
+	site, err := ch.build(building)
+	if err != nil {
+		return err
+	}
+	if site == nil {
+		ch.Game.Display.ShowMessage("Built a new "+building, "success")
+		return nil
 	}
 
-	building := args[0]
+	message := "Started building a " + building + " (site #" + strconv.Itoa(site.ID) + ", " +
+		strconv.FormatFloat(site.Required, 'f', 0, 64) + " builder-ticks of work)"
+	if ch.Game.Villagers.GetAssigned(ConstructionTask) == 0 {
+		message += ". Assign builders with 'assign villager " + ConstructionTask + " <count>'."
+	}
+	ch.Game.Display.ShowMessage(message, "success")
+	return nil
+}
 
-	// Check if building is available in current age
-	currentAgeIndex := ch.Game.Progress.GetCurrentAgeIndex(ch.Game.Age)
-	availableBuildings := []string{}
+// build pays for a building if it is available and affordable and starts
+// constructing it. Buildings that take no time are finished at once, and
+// no construction site is returned for them.
+func (ch *CommandHandler) build(building string) (*ConstructionSite, error) {
+	if err := ch.checkBuildable(building); err != nil {
+		return nil, err
+	}
 
-	for i, age := range ch.Game.Progress.GetAllAges() {
-		if i <= currentAgeIndex {
-			availableBuildings = append(availableBuildings, ch.Game.Progress.GetUnlocks(age).Buildings...)
-		}
+	// Try to build
+	site, ok := ch.Game.Buildings.Build(building, ch.Game.Resources)
+	if !ok {
+		return nil, errors.New("Failed to build " + building + ". Required resources: " + ch.describeBuildingCost(building))
 	}
 
+	if site == nil {
+		ch.Game.recordBuilt(building)
+	}
+	return site, nil
+}
+
+// checkBuildable checks that a building is available in the current age and
+// isn't an upgrade tier, which can only be reached with 'upgrade'
+func (ch *CommandHandler) checkBuildable(building string) error {
+	// Check if building is available in current age
 	buildingAvailable := false
-	for _, b := range availableBuildings {
+	for _, b := range ch.availableBuildings() {
 		if b == building {
 			buildingAvailable = true
 			break
@@ -152,105 +439,183 @@ func (ch *CommandHandler) CmdBuild(args []string) {
 	}
 
 	if !buildingAvailable {
-		ch.Game.Display.ShowMessage(building+" is not available in the "+ch.Game.Age, "error")
-		return
+		return errors.New(building + " is not available in the " + ch.Game.Age)
+	}
+	if base := ch.Game.Buildings.GetUpgradeBase(building); base != "" {
+		return errors.New(building + " is built by upgrading a " + base + ". Use 'upgrade " + base + "'.")
 	}
+	return nil
+}
 
-	// Try to build
-	if ch.Game.Buildings.Build(building, ch.Game.Resources) {
-		ch.Game.Display.ShowMessage("Built a new "+building, "success")
+// describeBuildingCost renders a building's current cost
+func (ch *CommandHandler) describeBuildingCost(building string) string {
+	return describeCost(ch.Game.Buildings.GetCost(building))
+}
+
+// CmdUpgrade upgrades buildings to their next tier
+func (ch *CommandHandler) CmdUpgrade(args []string) error {
+	building := strings.ToLower(args[0])
+	count := 1
+	if len(args) == 2 {
+		count, _ = strconv.Atoi(args[1])
+	}
+
+	var sites []*ConstructionSite
+	upgraded := 0
+	var err error
+	for upgraded+len(sites) < count {
+		var site *ConstructionSite
+		if site, err = ch.upgrade(building); err != nil {
+			break
+		}
+		if site != nil {
+			sites = append(sites, site)
+		} else {
+			upgraded++
+		}
+	}
+	if upgraded+len(sites) == 0 {
+		return err
+	}
+
+	tier := ch.Game.Buildings.GetUpgrade(building)
+	if upgraded > 0 {
+		ch.Game.Display.ShowMessage("Upgraded "+describeUpgrade(upgraded, building, tier), "success")
+	}
+	if len(sites) > 0 {
+		message := "Started upgrading " + describeUpgrade(len(sites), building, tier) + " (" +
+			strconv.FormatFloat(sites[0].Required, 'f', 0, 64) + " builder-ticks of work each)"
+		if ch.Game.Villagers.GetAssigned(ConstructionTask) == 0 {
+			message += ". Assign builders with 'assign villager " + ConstructionTask + " <count>'."
+		}
+		ch.Game.Display.ShowMessage(message, "success")
+	}
+	if err != nil {
+		ch.Game.Display.ShowMessage(err.Error(), "warning")
+	}
+	return nil
+}
+
+// describeUpgrade renders an upgrade such as "a hut to a house" or
+// "3 huts to houses"
+func describeUpgrade(count int, building, tier string) string {
+	if count == 1 {
+		return "a " + building + " to a " + tier
+	}
+	return strconv.Itoa(count) + " " + building + "s to " + tier + "s"
+}
+
+// upgrade pays for upgrading one building to its next tier if the tier is
+// available and affordable and starts the work. Upgrades that take no time
+// are finished at once, and no construction site is returned for them.
+func (ch *CommandHandler) upgrade(building string) (*ConstructionSite, error) {
+	tier := ch.Game.Buildings.GetUpgrade(building)
+	if tier == "" {
+		if _, exists := ch.Game.Buildings.GetAll()[building]; !exists {
+			return nil, errors.New("Unknown building: " + building)
+		}
+		return nil, errors.New(building + " has no upgrade")
+	}
+	if !containsFold(ch.availableBuildings(), tier) {
+		return nil, errors.New("Upgrading to " + tier + " is not available in the " + ch.Game.Age)
+	}
+
+	switch {
+	case ch.Game.Buildings.GetCount(building) == 0:
+		return nil, errors.New("You have no " + building + " to upgrade")
+	case ch.Game.Buildings.GetUpgradable(building) == 0:
+		return nil, errors.New("Every " + building + " is already being upgraded")
+	}
+
+	site, ok := ch.Game.Buildings.Upgrade(building, ch.Game.Resources)
+	if !ok {
+		return nil, errors.New("Failed to upgrade " + building + " to " + tier + ". Required resources: " + ch.describeBuildingCost(tier))
+	}
+	if site == nil {
+		ch.Game.recordUpgraded(building, tier)
+	}
+	return site, nil
+}
+
+// availableBuildings lists the buildings unlocked by the current or earlier
+// ages, or by research
+func (ch *CommandHandler) availableBuildings() []string {
+	currentAgeIndex := ch.Game.Progress.GetCurrentAgeIndex(ch.Game.Age)
+	available := []string{}
 
-		// Track building in stats
-		ch.Game.Stats.AddEvent(ch.Game.Tick, "building_built", "Built a new "+building)
-		ch.Game.Stats.AddBuildingBuilt(building)
-	} else {
-		costs := ch.Game.Buildings.GetCost(building)
-		costStrs := []string{}
-		for res, amount := range costs {
-			costStrs = append(costStrs, strconv.FormatFloat(amount, 'f', 0, 64)+" "+res)
+	for i, age := range ch.Game.Progress.GetAllAges() {
+		if i <= currentAgeIndex {
+			available = append(available, ch.Game.Progress.GetUnlocks(age).Buildings...)
 		}
-		costStr := strings.Join(costStrs, ", ")
-		ch.Game.Display.ShowMessage("Failed to build "+building+". Required resources: "+costStr, "error")
 	}
+	return append(available, ch.Game.modifiers.UnlockedBuildings...)
+}
+
+// availableVillagers lists the villager types unlocked by the current or
+// earlier ages, or by research
+func (ch *CommandHandler) availableVillagers() []string {
+	currentAgeIndex := ch.Game.Progress.GetCurrentAgeIndex(ch.Game.Age)
+	available := []string{}
+
+	for i, age := range ch.Game.Progress.GetAllAges() {
+		if i <= currentAgeIndex {
+			available = append(available, ch.Game.Progress.GetUnlocks(age).Villagers...)
+		}
+	}
+	return append(available, ch.Game.modifiers.UnlockedVillagers...)
 }
 
 // CmdStatus shows detailed status
-func (ch *CommandHandler) CmdStatus() {
+func (ch *CommandHandler) CmdStatus(args []string) error {
 	// This is handled by the UI
+	return nil
 }
 
 // CmdAssign assigns villagers to tasks
-func (ch *CommandHandler) CmdAssign(args []string) {
-	if len(args) != 3 {
-		ch.Game.Display.ShowMessage("Usage: assign <villager_type> <resource> <count>", "error")
-		return
-	}
-
+func (ch *CommandHandler) CmdAssign(args []string) error {
 	villagerType := args[0]
 	resource := args[1]
-	count, err := strconv.Atoi(args[2])
-	if err != nil || count <= 0 {
-		ch.Game.Display.ShowMessage("Count must be a positive number", "error")
-		return
-	}
+	count, _ := strconv.Atoi(args[2])
 
 	// Try to assign villagers
-	if ch.Game.Villagers.Assign(villagerType, resource, count) {
-		ch.Game.Display.ShowMessage("Assigned "+strconv.Itoa(count)+" "+villagerType+"s to "+resource, "success")
-	} else {
-		ch.Game.Display.ShowMessage("Failed to assign "+villagerType+"s. Not enough idle villagers or invalid resource.", "error")
+	if !ch.Game.Villagers.Assign(villagerType, resource, count) {
+		return errors.New("Failed to assign " + villagerType + "s. Not enough idle villagers or invalid resource.")
 	}
+	ch.Game.Display.ShowMessage("Assigned "+strconv.Itoa(count)+" "+villagerType+"s to "+resource, "success")
+	return nil
 }
 
 // CmdUnassign unassigns villagers from tasks
-func (ch *CommandHandler) CmdUnassign(args []string) {
-	if len(args) != 3 {
-		ch.Game.Display.ShowMessage("Usage: unassign <villager_type> <resource> <count>", "error")
-		return
-	}
-
+func (ch *CommandHandler) CmdUnassign(args []string) error {
 	villagerType := args[0]
 	resource := args[1]
-	count, err := strconv.Atoi(args[2])
-	if err != nil || count <= 0 {
-		ch.Game.Display.ShowMessage("Count must be a positive number", "error")
-		return
-	}
+	count, _ := strconv.Atoi(args[2])
 
 	// Try to unassign villagers
-	if ch.Game.Villagers.Unassign(villagerType, resource, count) {
-		ch.Game.Display.ShowMessage("Unassigned "+strconv.Itoa(count)+" "+villagerType+"s from "+resource, "success")
-	} else {
-		ch.Game.Display.ShowMessage("Failed to unassign "+villagerType+"s. Not enough assigned to "+resource+".", "error")
+	if !ch.Game.Villagers.Unassign(villagerType, resource, count) {
+		return errors.New("Failed to unassign " + villagerType + "s. Not enough assigned to " + resource + ".")
 	}
+	ch.Game.Display.ShowMessage("Unassigned "+strconv.Itoa(count)+" "+villagerType+"s from "+resource, "success")
+	return nil
 }
 
 // CmdRecruit recruits new villagers
-func (ch *CommandHandler) CmdRecruit(args []string) {
-	if len(args) != 2 {
-		ch.Game.Display.ShowMessage("Usage: recruit <villager_type> <count>", "error")
-		return
-	}
-
+func (ch *CommandHandler) CmdRecruit(args []string) error {
 	villagerType := args[0]
-	count, err := strconv.Atoi(args[1])
-	if err != nil || count <= 0 {
-		ch.Game.Display.ShowMessage("Count must be a positive number", "error")
-		return
+	count, _ := strconv.Atoi(args[1])
+	if err := ch.recruit(villagerType, count); err != nil {
+		return err
 	}
+	ch.Game.Display.ShowMessage("Recruited "+strconv.Itoa(count)+" new "+villagerType+"s", "success")
+	return nil
+}
 
+// recruit adds villagers if they are available, there is housing for them
+// and their food cost can be paid
+func (ch *CommandHandler) recruit(villagerType string, count int) error {
 	// Check if villager type is available in current age
-	currentAgeIndex := ch.Game.Progress.GetCurrentAgeIndex(ch.Game.Age)
-	availableVillagers := []string{"villager"} // Villager always available
-
-	for i, age := range ch.Game.Progress.GetAllAges() {
-		if i <= currentAgeIndex {
-			availableVillagers = append(availableVillagers, ch.Game.Progress.GetUnlocks(age).Villagers...)
-		}
-	}
-
 	villagerAvailable := false
-	for _, v := range availableVillagers {
+	for _, v := range ch.availableVillagers() {
 		if v == villagerType {
 			villagerAvailable = true
 			break
@@ -258,8 +623,7 @@ func (ch *CommandHandler) CmdRecruit(args []string) {
 	}
 
 	if !villagerAvailable {
-		ch.Game.Display.ShowMessage(villagerType+" is not available in the "+ch.Game.Age, "error")
-		return
+		return errors.New(villagerType + " is not available in the " + ch.Game.Age)
 	}
 
 	// Check villager capacity
@@ -270,8 +634,7 @@ func (ch *CommandHandler) CmdRecruit(args []string) {
 	}
 
 	if totalVillagers+count > capacity {
-		ch.Game.Display.ShowMessage("Not enough housing capacity. Current: "+strconv.Itoa(totalVillagers)+"/"+strconv.Itoa(capacity), "error")
-		return
+		return errors.New("Not enough housing capacity. Current: " + strconv.Itoa(totalVillagers) + "/" + strconv.Itoa(capacity))
 	}
 
 	// Get food cost
@@ -285,82 +648,369 @@ func (ch *CommandHandler) CmdRecruit(args []string) {
 
 	// Check if there's enough total food (from all food sources)
 	if !ch.Game.Resources.HasFood(foodCost) {
-		ch.Game.Display.ShowMessage("Not enough food. Need "+strconv.FormatFloat(foodCost, 'f', 0, 64)+" food.", "error")
-		return
+		return errors.New("Not enough food. Need " + strconv.FormatFloat(foodCost, 'f', 0, 64) + " food.")
 	}
 
 	// Recruit villagers
 	ch.Game.Resources.RemoveFood(foodCost)
 	ch.Game.Villagers.Add(villagerType, count)
-	ch.Game.Display.ShowMessage("Recruited "+strconv.Itoa(count)+" new "+villagerType+"s", "success")
 
 	// Track recruitment in stats
 	ch.Game.Stats.AddEvent(ch.Game.Tick, "villager_recruited", "Recruited "+strconv.Itoa(count)+" new "+villagerType+"s")
 	for i := 0; i < count; i++ {
 		ch.Game.Stats.AddVillagerRecruited(villagerType)
 	}
+	return nil
 }
 
 // CmdBuildings lists available buildings and their costs
-func (ch *CommandHandler) CmdBuildings() {
+func (ch *CommandHandler) CmdBuildings(args []string) error {
 	// This will be handled by the UI
+	return nil
 }
 
 // CmdSave saves the current game
-func (ch *CommandHandler) CmdSave(args []string) {
-	if len(args) != 1 {
-		ch.Game.Display.ShowMessage("Usage: save <filename>", "error")
-		return
-	}
-
+func (ch *CommandHandler) CmdSave(args []string) error {
 	filename := args[0]
-	err := ch.Game.SaveGame(filename)
+	err := ch.Game.saveGame(filename)
 	if err != nil {
-		ch.Game.Display.ShowMessage("Failed to save game: "+err.Error(), "error")
-		return
+		return errors.New("Failed to save game: " + err.Error())
 	}
 
 	ch.Game.Display.ShowMessage("Game saved as '"+filename+"'", "success")
+	return nil
 }
 
 // CmdLoad loads a saved game
-func (ch *CommandHandler) CmdLoad(args []string) {
-	if len(args) != 1 {
-		ch.Game.Display.ShowMessage("Usage: load <filename>", "error")
-		return
-	}
-
+func (ch *CommandHandler) CmdLoad(args []string) error {
 	filename := args[0]
-	err := ch.Game.LoadGame(filename)
-	if err != nil {
-		ch.Game.Display.ShowMessage("Failed to load game: "+err.Error(), "error")
-		return
+	err := ch.Game.loadGame(filename, len(args) == 2)
+	if errors.Is(err, ErrSaveTampered) {
+		return errors.New("Failed to load game: " + err.Error() + ". Use 'load " + filename + " unverified' to load it anyway")
+	} else if err != nil {
+		return errors.New("Failed to load game: " + err.Error())
 	}
 
 	ch.Game.Display.ShowMessage("Game '"+filename+"' loaded successfully", "success")
+	return nil
 }
 
 // CmdListSaves lists all saved games
-func (ch *CommandHandler) CmdListSaves() {
+func (ch *CommandHandler) CmdListSaves(args []string) error {
 	saves, err := ListSaves()
 	if err != nil {
-		ch.Game.Display.ShowMessage("Failed to list saves: "+err.Error(), "error")
-		return
+		return errors.New("Failed to list saves: " + err.Error())
 	}
 
 	if len(saves) == 0 {
 		ch.Game.Display.ShowMessage("No saved games found", "info")
-		return
+		return nil
 	}
 
 	ch.Game.Display.ShowMessage("Available saved games:", "info")
 	for _, save := range saves {
 		ch.Game.Display.ShowMessage("- "+save, "info")
 	}
+	return nil
+}
+
+// CmdName names the civilization; the name is shown when browsing saves
+func (ch *CommandHandler) CmdName(args []string) error {
+	if len(args) == 0 {
+		if ch.Game.CivName == "" {
+			ch.Game.Display.ShowMessage("Your civilization has no name yet. Usage: name <civilization name>", "info")
+		} else {
+			ch.Game.Display.ShowMessage("Your civilization is called "+ch.Game.CivName, "info")
+		}
+		return nil
+	}
+
+	ch.Game.CivName = strings.Join(args, " ")
+	ch.Game.Display.ShowMessage("Your civilization is now called "+ch.Game.CivName, "success")
+	return nil
+}
+
+// CmdExport writes a save as a compressed .civsave file and shows it as a
+// string that can be pasted elsewhere
+func (ch *CommandHandler) CmdExport(args []string) error {
+	name := args[0]
+	compressed, err := ExportSave(name)
+	if err != nil {
+		return errors.New("Failed to export save: " + err.Error())
+	}
+
+	exportPath := filepath.Join(SaveDir(), name+ExportExtension)
+	if err := writeFileAtomic(exportPath, compressed); err != nil {
+		return errors.New("Failed to write export file: " + err.Error())
+	}
+
+	ch.Game.Display.ShowMessage("Exported '"+name+"' to "+exportPath, "success")
+	ch.Game.Display.ShowMessage("Export string (import it with 'import <string> <name>'):", "info")
+	ch.Game.Display.ShowMessage(EncodeExportString(compressed), "info")
+	return nil
+}
+
+// CmdImport reconstructs a save from an export string or .civsave file
+func (ch *CommandHandler) CmdImport(args []string) error {
+	compressed, err := ReadExport(args[0])
+	if err != nil {
+		return errors.New("Failed to import save: " + err.Error())
+	}
+
+	name := args[1]
+	if err := ImportSave(compressed, name); err != nil {
+		return errors.New("Failed to import save: " + err.Error())
+	}
+
+	ch.Game.Display.ShowMessage("Imported save as '"+name+"'. Use 'load "+name+"' to play it.", "success")
+	return nil
+}
+
+// CmdDiff compares two saves, as text or as JSON for scripts
+func (ch *CommandHandler) CmdDiff(args []string) error {
+	asJSON := len(args) == 3
+	diff, err := DiffSaveFiles(args[0], args[1])
+	if err != nil {
+		return errors.New("Failed to compare saves: " + err.Error())
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return errors.New("Failed to encode diff: " + err.Error())
+		}
+		ch.Game.Display.ShowMessage(string(data), "info")
+		return nil
+	}
+
+	for i, line := range diff.Lines() {
+		style := "info"
+		if i == 0 || strings.HasSuffix(line, ":") {
+			style = "highlight"
+		}
+		ch.Game.Display.ShowMessage(line, style)
+	}
+	return nil
+}
+
+// CmdRun runs a script file, or stops the script running in the background
+func (ch *CommandHandler) CmdRun(args []string) error {
+	if strings.EqualFold(args[0], "stop") && len(args) == 1 {
+		if !ch.Game.stopScript() {
+			return errors.New("No script is running")
+		}
+		return nil
+	}
+
+	script, err := ReadScript(args[0])
+	if err != nil {
+		return errors.New("Failed to run script: " + err.Error())
+	}
+	script.StopOnError = len(args) == 2
+
+	if ch.Game.live {
+		return ch.Game.startScript(script)
+	}
+	if err := ch.Game.runScript(script); err != nil {
+		return errors.New("Script stopped at " + err.Error())
+	}
+	return nil
+}
+
+// CmdConstruction lists buildings under construction or cancels one
+func (ch *CommandHandler) CmdConstruction(args []string) error {
+	if len(args) == 0 {
+		ch.listConstruction()
+		return nil
+	}
+	if len(args) != 2 {
+		return errors.New("Usage: construction cancel <site>")
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil {
+		return errors.New("Usage: construction cancel <site>")
+	}
+	site, refund, exists := ch.Game.Buildings.CancelConstruction(id, ch.Game.Resources)
+	if !exists {
+		return errors.New("There is no construction site #" + strconv.Itoa(id) + ". Use 'construction' to see them.")
+	}
+	ch.Game.Display.ShowMessage("Cancelled the "+site.Label()+" at site #"+strconv.Itoa(id)+"; refunded "+describeCost(refund), "success")
+	return nil
+}
+
+// listConstruction shows the buildings under construction, in the order
+// builders work on them
+func (ch *CommandHandler) listConstruction() {
+	sites := ch.Game.Buildings.GetConstruction()
+	if len(sites) == 0 {
+		ch.Game.Display.ShowMessage("Nothing is under construction.", "info")
+		return
+	}
+
+	builders := ch.Game.Villagers.GetAssigned(ConstructionTask)
+	ch.Game.Display.ShowMessage("=== Under Construction ("+strconv.Itoa(builders)+" builders) ===", "highlight")
+	for _, site := range sites {
+		ch.Game.Display.ShowMessage("#"+strconv.Itoa(site.ID)+" "+site.Label()+": "+
+			strconv.FormatFloat(site.Work, 'f', 0, 64)+"/"+strconv.FormatFloat(site.Required, 'f', 0, 64)+
+			" ("+strconv.FormatFloat(site.Progress()*100, 'f', 0, 64)+"%)", "info")
+	}
+	if builders == 0 {
+		ch.Game.Display.ShowMessage("No villagers are building. Assign some with 'assign villager "+ConstructionTask+" <count>'.", "warning")
+	}
+}
+
+// CmdQueue adds, lists, cancels and reorders build orders
+func (ch *CommandHandler) CmdQueue(args []string) error {
+	if len(args) == 0 || strings.EqualFold(args[0], "list") {
+		ch.listQueue()
+		return nil
+	}
+
+	action := strings.ToLower(args[0])
+	if action == "build" {
+		if len(args) < 2 || len(args) > 3 {
+			return errors.New("Usage: queue build <building> [count]")
+		}
+		building := args[1]
+		count := 1
+		if len(args) == 3 {
+			var err error
+			if count, err = strconv.Atoi(args[2]); err != nil || count <= 0 {
+				return errors.New("Count must be a positive number")
+			}
+		}
+		if err := ch.checkBuildable(strings.ToLower(building)); err != nil {
+			return err
+		}
+
+		order := ch.Game.Queue.Add(strings.ToLower(building), count)
+		ch.Game.Display.ShowMessage("Queued order #"+strconv.Itoa(order.ID)+": "+strconv.Itoa(count)+" "+order.Building+
+			" (position "+strconv.Itoa(len(ch.Game.Queue.GetAll()))+")", "success")
+		return nil
+	}
+
+	usage := "Usage: queue cancel <order>"
+	if action == "reorder" {
+		usage = "Usage: queue reorder <order> <position>"
+	}
+	if (action == "cancel" && len(args) != 2) || (action == "reorder" && len(args) != 3) {
+		return errors.New(usage)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil {
+		return errors.New(usage)
+	}
+
+	switch action {
+	case "cancel":
+		order, exists := ch.Game.Queue.Cancel(id)
+		if !exists {
+			return errors.New("There is no order #" + strconv.Itoa(id) + " in the queue. Use 'queue list' to see it.")
+		}
+		ch.Game.Display.ShowMessage("Cancelled order #"+strconv.Itoa(id)+" ("+strconv.Itoa(order.Remaining())+" "+
+			order.Building+" not built)", "success")
+	case "reorder":
+		position, err := strconv.Atoi(args[2])
+		if err != nil || position <= 0 {
+			return errors.New("Position must be a positive number")
+		}
+		if !ch.Game.Queue.Move(id, position) {
+			return errors.New("There is no order #" + strconv.Itoa(id) + " in the queue. Use 'queue list' to see it.")
+		}
+		position = min(position, len(ch.Game.Queue.GetAll()))
+		ch.Game.Display.ShowMessage("Moved order #"+strconv.Itoa(id)+" to position "+strconv.Itoa(position), "success")
+	}
+	return nil
+}
+
+// listQueue shows the build orders in the order they will be built
+func (ch *CommandHandler) listQueue() {
+	orders := ch.Game.Queue.GetAll()
+	if len(orders) == 0 {
+		ch.Game.Display.ShowMessage("The build queue is empty. Add to it with e.g. 'queue build hut 2'.", "info")
+		return
+	}
+
+	ch.Game.Display.ShowMessage("=== Build Queue ===", "highlight")
+	for i, order := range orders {
+		ch.Game.Display.ShowMessage(strconv.Itoa(i+1)+". #"+strconv.Itoa(order.ID)+" "+order.Building+" "+
+			strconv.Itoa(order.Built)+"/"+strconv.Itoa(order.Count)+" - next costs "+
+			describeCost(ch.Game.Buildings.GetCost(order.Building)), "info")
+		if i == 0 && order.blocked != "" {
+			ch.Game.Display.ShowMessage("   Waiting: "+order.blocked, "warning")
+		}
+	}
+}
+
+// CmdRule adds, lists, removes, enables and disables automation rules
+func (ch *CommandHandler) CmdRule(args []string) error {
+	if len(args) == 0 || strings.EqualFold(args[0], "list") {
+		ch.listRules()
+		return nil
+	}
+
+	action := strings.ToLower(args[0])
+	if action == "add" {
+		if len(args) == 1 {
+			return errors.New("Usage: rule add when <condition> <action>")
+		}
+		rule, err := ch.Game.parseRule(strings.Join(args[1:], " "))
+		if err != nil {
+			return errors.New("Invalid rule: " + err.Error())
+		}
+		ch.Game.Rules.Add(rule)
+		ch.Game.Display.ShowMessage("Added rule #"+strconv.Itoa(rule.ID)+": "+rule.Text, "success")
+		return nil
+	}
+
+	if len(args) != 2 {
+		return errors.New("Usage: rule " + action + " <number>")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[1], "#"))
+	if err != nil {
+		return errors.New("Rule number must be a number, e.g. 'rule " + action + " 1'")
+	}
+	rule, exists := ch.Game.Rules.Get(id)
+	if !exists {
+		return errors.New("There is no rule #" + strconv.Itoa(id) + ". Use 'rule list' to see your rules.")
+	}
+
+	switch action {
+	case "remove":
+		ch.Game.Rules.Remove(id)
+		ch.Game.Display.ShowMessage("Removed rule #"+strconv.Itoa(id)+": "+rule.Text, "success")
+	case "enable":
+		rule.Enabled = true
+		ch.Game.Display.ShowMessage("Enabled rule #"+strconv.Itoa(id), "success")
+	case "disable":
+		rule.Enabled = false
+		ch.Game.Display.ShowMessage("Disabled rule #"+strconv.Itoa(id), "success")
+	}
+	return nil
+}
+
+// listRules shows every rule with its state and how often it has fired
+func (ch *CommandHandler) listRules() {
+	rules := ch.Game.Rules.GetAll()
+	if len(rules) == 0 {
+		ch.Game.Display.ShowMessage("No rules yet. Add one with e.g. 'rule add when wood >= 20 build hut'.", "info")
+		return
+	}
+
+	ch.Game.Display.ShowMessage("=== Rules ===", "highlight")
+	for _, rule := range rules {
+		state := "on"
+		if !rule.Enabled {
+			state = "off"
+		}
+		ch.Game.Display.ShowMessage("#"+strconv.Itoa(rule.ID)+" ["+state+"] "+rule.Text+
+			" (fired "+strconv.Itoa(rule.Fired)+" times)", "info")
+	}
 }
 
 // CmdStats shows game statistics
-func (ch *CommandHandler) CmdStats() {
+func (ch *CommandHandler) CmdStats(args []string) error {
 	// Calculate play time
 	playTime := ch.Game.Stats.GetPlayTime()
 
@@ -415,59 +1065,88 @@ func (ch *CommandHandler) CmdStats() {
 			"info",
 		)
 	}
+	return nil
 }
 
 // CmdResearch starts researching a technology
-func (ch *CommandHandler) CmdResearch(args []string) {
-	if len(args) != 1 {
-		ch.Game.Display.ShowMessage("Usage: research <technology>", "error")
-		return
+func (ch *CommandHandler) CmdResearch(args []string) error {
+	if len(args) == 1 && strings.EqualFold(args[0], "cancel") {
+		return errors.New("Usage: research cancel <technology>")
+	}
+	if len(args) == 2 {
+		if !strings.EqualFold(args[0], "cancel") {
+			return errors.New("Usage: research <technology> | research cancel <technology>")
+		}
+		removed := ch.Game.Research.Dequeue(args[1])
+		if len(removed) == 0 {
+			return errors.New(args[1] + " is not in the research queue")
+		}
+		ch.Game.Display.ShowMessage("Removed from the research queue: "+strings.Join(removed, ", "), "success")
+		return nil
 	}
 
-	techName := args[0]
+	added, err := ch.Game.Research.Enqueue(args[0], ch.Game.Age)
+	if err != nil {
+		return err
+	}
+	techName := added[len(added)-1]
+	if len(added) > 1 {
+		ch.Game.Display.ShowMessage("Queued prerequisites first: "+strings.Join(added[:len(added)-1], ", "), "info")
+	}
 
+	// Start right away if nothing else is being researched
+	ch.Game.startQueuedResearch()
+	if queue := ch.Game.Research.GetQueue(); len(queue) > 0 && queue[len(queue)-1] == techName {
+		message := "Added " + techName + " to the research queue (position " + strconv.Itoa(len(queue)) + ")"
+		if ch.Game.Resources.Get("knowledge") <= 0 {
+			message += ". Research starts once you have knowledge points; assign villagers to gather knowledge."
+		}
+		ch.Game.Display.ShowMessage(message, "success")
+	}
+	return nil
+}
+
+// startResearch begins researching a technology if it is available and
+// nothing else is being researched
+func (ch *CommandHandler) startResearch(techName string) error {
 	// Check if the technology is available
 	availableTechs := ch.Game.Research.GetAvailableTechnologies(ch.Game.Age)
 	tech, exists := availableTechs[techName]
 
 	if !exists {
-		ch.Game.Display.ShowMessage("Technology '"+techName+"' is not available for research.", "error")
-		return
+		return errors.New("Technology '" + techName + "' is not available for research.")
 	}
 
 	// Check if we're already researching something
 	currentTech, progress, _ := ch.Game.Research.GetProgress()
 	if currentTech != "" {
-		ch.Game.Display.ShowMessage("You are already researching "+currentTech+" ("+
-			strconv.FormatFloat(progress, 'f', 1, 64)+" / "+
-			strconv.FormatFloat(tech.Cost, 'f', 1, 64)+")", "error")
-		return
+		return errors.New("You are already researching " + currentTech + " (" +
+			strconv.FormatFloat(progress, 'f', 1, 64) + " / " +
+			strconv.FormatFloat(tech.Cost, 'f', 1, 64) + ")")
 	}
 
 	// Check if the player has any knowledge points
 	knowledgePoints := ch.Game.Resources.Get("knowledge")
 	if knowledgePoints <= 0 {
-		ch.Game.Display.ShowMessage("You cannot research any technology without knowledge points. Assign villagers to gather knowledge.", "error")
-		return
+		return errors.New("You cannot research any technology without knowledge points. Assign villagers to gather knowledge.")
 	}
 
 	// Start research
-	if ch.Game.Research.StartResearch(techName, 0) {
-		ch.Game.Display.ShowMessage("Started researching "+techName, "success")
-		ch.Game.Stats.AddEvent(ch.Game.Tick, "research_started", "Started researching "+techName)
-	} else {
-		ch.Game.Display.ShowMessage("Failed to start research on "+techName, "error")
+	if !ch.Game.Research.StartResearch(techName, 0) {
+		return errors.New("Failed to start research on " + techName)
 	}
+	ch.Game.Stats.AddEvent(ch.Game.Tick, "research_started", "Started researching "+techName)
+	return nil
 }
 
 // CmdTechs lists available technologies
-func (ch *CommandHandler) CmdTechs() {
+func (ch *CommandHandler) CmdTechs(args []string) error {
 	// Get available technologies
 	availableTechs := ch.Game.Research.GetAvailableTechnologies(ch.Game.Age)
 
 	if len(availableTechs) == 0 {
 		ch.Game.Display.ShowMessage("No technologies available for research in the "+ch.Game.Age, "info")
-		return
+		return nil
 	}
 
 	// Check if the player has any knowledge points
@@ -501,4 +1180,52 @@ func (ch *CommandHandler) CmdTechs() {
 			ch.Game.Display.ShowMessage(name+": "+tech.Description, "info")
 		}
 	}
+	return nil
+}
+
+// CmdPause pauses the game clock
+func (ch *CommandHandler) CmdPause(args []string) error {
+	if ch.Game.Paused {
+		ch.Game.Display.ShowMessage("The game is already paused", "warning")
+		return nil
+	}
+
+	ch.Game.setPaused(true)
+	ch.Game.Display.ShowMessage("Game paused. Type 'resume' to continue.", "warning")
+	return nil
+}
+
+// CmdResume resumes the game clock
+func (ch *CommandHandler) CmdResume(args []string) error {
+	if !ch.Game.Paused {
+		ch.Game.Display.ShowMessage("The game is not paused", "warning")
+		return nil
+	}
+
+	ch.Game.setPaused(false)
+	ch.Game.Display.ShowMessage("Game resumed at "+formatSpeed(ch.Game.Speed), "success")
+	return nil
+}
+
+// CmdSpeed shows or changes the game speed
+func (ch *CommandHandler) CmdSpeed(args []string) error {
+	if len(args) == 0 {
+		ch.Game.Display.ShowMessage("Game speed: "+formatSpeed(ch.Game.Speed)+" ("+
+			strconv.FormatFloat(ch.Game.TickDuration.Seconds(), 'f', 2, 64)+"s per tick)", "info")
+		return nil
+	}
+
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(args[0]), "x"), 64)
+	if err != nil || speed < MinSpeed || speed > MaxSpeed {
+		return errors.New("Speed must be a number between " + formatSpeed(MinSpeed) + " and " + formatSpeed(MaxSpeed))
+	}
+
+	ch.Game.setSpeed(speed)
+	ch.Game.Display.ShowMessage("Game speed set to "+formatSpeed(speed), "success")
+	return nil
+}
+
+// formatSpeed renders a speed multiplier such as "2x" or "0.5x"
+func formatSpeed(speed float64) string {
+	return strconv.FormatFloat(speed, 'f', -1, 64) + "x"
 }
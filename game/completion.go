@@ -0,0 +1,271 @@
+package game
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxSuggestionDistance is the largest edit distance at which an unknown
+// command still gets a "did you mean" suggestion
+const maxSuggestionDistance = 2
+
+// ArgCompleter lists the values an argument can currently take, given the
+// arguments typed before it
+type ArgCompleter func(ch *CommandHandler, prior []string) []string
+
+// CompleteCommand returns the possible completions of a partially typed
+// command line while holding the engine lock
+func (ge *GameEngine) CompleteCommand(input string) []string {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	if ge.Commands == nil {
+		return nil
+	}
+	return ge.Commands.Complete(input)
+}
+
+// Complete returns the possible completions of a partially typed command
+// line. Each completion is the whole line with the word being typed filled
+// in, followed by a space when more arguments are expected.
+func (ch *CommandHandler) Complete(input string) []string {
+	tokens := strings.Fields(input)
+	typingNew := input == "" || strings.HasSuffix(input, " ")
+	if typingNew {
+		tokens = append(tokens, "")
+	}
+	partial := tokens[len(tokens)-1]
+	base := strings.Join(tokens[:len(tokens)-1], " ")
+	if base != "" {
+		base += " "
+	}
+
+	// Completing the command name itself
+	if len(tokens) == 1 {
+		lines := []string{}
+		for _, cmd := range ch.Registry.Commands() {
+			lines = append(lines, completions("", partial, []string{cmd.Name}, len(cmd.Args) > 0)...)
+		}
+		return lines
+	}
+
+	cmd, exists := ch.Registry.Lookup(tokens[0])
+	if !exists {
+		return nil
+	}
+	argIndex := len(tokens) - 2
+	if argIndex >= len(cmd.Args) {
+		return nil
+	}
+
+	spec := cmd.Args[argIndex]
+	values := spec.Choices
+	if spec.Complete != nil {
+		values = spec.Complete(ch, tokens[1:len(tokens)-1])
+	}
+	return completions(base, partial, values, argIndex < len(cmd.Args)-1)
+}
+
+// completions filters values by the typed prefix, keeping their order, and
+// turns them into lines
+func completions(base, partial string, values []string, more bool) []string {
+	seen := make(map[string]bool)
+	lines := []string{}
+	for _, value := range values {
+		if seen[value] || !strings.HasPrefix(strings.ToLower(value), strings.ToLower(partial)) {
+			continue
+		}
+		seen[value] = true
+
+		line := base + value
+		if more {
+			line += " "
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Suggest returns the registered name or alias closest to an unknown
+// command, or "" if nothing is close enough
+func (r *CommandRegistry) Suggest(name string) string {
+	name = strings.ToLower(name)
+	candidates := make([]string, 0, len(r.commands)+len(r.aliases))
+	for cmdName := range r.commands {
+		candidates = append(candidates, cmdName)
+	}
+	for alias := range r.aliases {
+		candidates = append(candidates, alias)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		distance := levenshtein(name, candidate)
+		// Very short names are too easy to match by accident
+		if distance < bestDistance && distance < len(candidate) && distance < len(name) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// Completers for the built-in commands' arguments
+
+func completeCommands(ch *CommandHandler, prior []string) []string {
+	names := []string{}
+	for _, cmd := range ch.Registry.Commands() {
+		names = append(names, cmd.Name)
+	}
+	return names
+}
+
+func completeBuildings(ch *CommandHandler, prior []string) []string {
+	buildable := []string{}
+	for _, building := range ch.availableBuildings() {
+		if ch.Game.Buildings.GetUpgradeBase(building) == "" {
+			buildable = append(buildable, building)
+		}
+	}
+	return buildable
+}
+
+// completeUpgrades lists the buildings you own whose next tier is available
+func completeUpgrades(ch *CommandHandler, prior []string) []string {
+	available := ch.availableBuildings()
+	upgradable := []string{}
+	for building, count := range ch.Game.Buildings.GetAll() {
+		if tier := ch.Game.Buildings.GetUpgrade(building); count > 0 && tier != "" && containsFold(available, tier) {
+			upgradable = append(upgradable, building)
+		}
+	}
+	sort.Strings(upgradable)
+	return upgradable
+}
+
+func completeVillagers(ch *CommandHandler, prior []string) []string {
+	return ch.availableVillagers()
+}
+
+// completeTasks lists the resources the villager type typed before can work on
+func completeTasks(ch *CommandHandler, prior []string) []string {
+	if len(prior) == 0 {
+		return nil
+	}
+	return ch.Game.Villagers.GetTasks(prior[len(prior)-1])
+}
+
+// completeGatherTasks lists the resources basic villagers can gather
+func completeGatherTasks(ch *CommandHandler, prior []string) []string {
+	return ch.Game.Villagers.GetTasks("villager")
+}
+
+// completeTechnologies lists the technologies that can be added to the
+// research queue, which includes those whose prerequisites are still missing
+func completeTechnologies(ch *CommandHandler, prior []string) []string {
+	current, _, _ := ch.Game.Research.GetProgress()
+	queued := ch.Game.Research.GetQueue()
+	ageIndex := ch.Game.Progress.GetCurrentAgeIndex(ch.Game.Age)
+
+	techs := []string{}
+	for name, tech := range ch.Game.Research.GetAllTechnologies() {
+		if ch.Game.Research.IsResearched(name) || name == current || containsFold(queued, name) ||
+			ch.Game.Progress.GetCurrentAgeIndex(tech.Age) > ageIndex {
+			continue
+		}
+		techs = append(techs, name)
+	}
+	sort.Strings(techs)
+	if len(queued) > 0 {
+		techs = append(techs, "cancel")
+	}
+	return techs
+}
+
+// completeQueuedTechnologies lists the technologies 'research cancel' can remove
+func completeQueuedTechnologies(ch *CommandHandler, prior []string) []string {
+	if len(prior) == 0 || !strings.EqualFold(prior[0], "cancel") {
+		return nil
+	}
+	return ch.Game.Research.GetQueue()
+}
+
+func completeSaves(ch *CommandHandler, prior []string) []string {
+	saves, err := ListSaves()
+	if err != nil {
+		return nil
+	}
+	sort.Strings(saves)
+	return saves
+}
+
+func completeRun(ch *CommandHandler, prior []string) []string {
+	return []string{"stop"}
+}
+
+// completeSites lists the construction sites that can be cancelled
+func completeSites(ch *CommandHandler, prior []string) []string {
+	ids := []string{}
+	for _, site := range ch.Game.Buildings.GetConstruction() {
+		ids = append(ids, strconv.Itoa(site.ID))
+	}
+	return ids
+}
+
+// completeQueue lists the buildings that can be queued, or the order numbers
+func completeQueue(ch *CommandHandler, prior []string) []string {
+	if len(prior) == 0 {
+		return nil
+	}
+	if strings.EqualFold(prior[0], "build") {
+		return completeBuildings(ch, prior)
+	}
+	ids := []string{}
+	for _, order := range ch.Game.Queue.GetAll() {
+		ids = append(ids, strconv.Itoa(order.ID))
+	}
+	return ids
+}
+
+// completeRules suggests how to start a new rule, or lists the rule numbers
+func completeRules(ch *CommandHandler, prior []string) []string {
+	if len(prior) == 0 {
+		return nil
+	}
+	if strings.EqualFold(prior[0], "add") {
+		return []string{"when"}
+	}
+	ids := []string{}
+	for _, rule := range ch.Game.Rules.GetAll() {
+		ids = append(ids, strconv.Itoa(rule.ID))
+	}
+	return ids
+}
+
+func completeSpeeds(ch *CommandHandler, prior []string) []string {
+	return []string{"0.5x", "1x", "2x", "5x", "10x"}
+}
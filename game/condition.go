@@ -0,0 +1,229 @@
+package game
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Condition is a test on the game state, e.g. "wood >= 20 and idle > 0". It
+// is used by script waits and automation rules. A condition is one or more
+// clauses joined by "and"; each clause is one of:
+//
+//	<quantity> <op> <number>   op is one of < <= > >= == !=
+//	age <op> <age name>        compares ages by their order
+//	researched <technology>
+//	research idle              nothing is being researched
+//
+// Quantities are resource, building and villager type names, "food" (all
+// food sources), "population", "idle" (idle villagers, optionally "idle
+// <villager type>"), "capacity" and "tick".
+type Condition struct {
+	Text    string
+	clauses []conditionClause
+}
+
+// clauseKind identifies the form of a condition clause
+type clauseKind int
+
+const (
+	clauseQuantity clauseKind = iota
+	clauseAge
+	clauseResearched
+	clauseResearchIdle
+)
+
+// conditionClause is a single test within a condition
+type conditionClause struct {
+	kind     clauseKind
+	quantity string  // For clauseQuantity
+	op       string  // For clauseQuantity and clauseAge
+	value    float64 // Number compared against, or the age index for clauseAge
+	name     string  // Technology for clauseResearched
+}
+
+// comparisonPattern splits a clause into quantity, operator and value
+var comparisonPattern = regexp.MustCompile(`^(.+?)\s*(>=|<=|==|!=|>|<|=)\s*(.+)$`)
+
+// quantityAliases maps alternative spellings to the quantity names used internally
+var quantityAliases = map[string]string{
+	"idle villagers": "idle",
+	"villagers":      "population",
+}
+
+// parseCondition parses a condition, checking that every name it refers to
+// exists in the game. The caller must hold the engine lock.
+func (ge *GameEngine) parseCondition(text string) (*Condition, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	condition := &Condition{Text: text}
+	for _, part := range splitClauses(text) {
+		clause, err := ge.parseClause(part)
+		if err != nil {
+			return nil, err
+		}
+		condition.clauses = append(condition.clauses, clause)
+	}
+	return condition, nil
+}
+
+// splitClauses splits a condition on the word "and"
+func splitClauses(text string) []string {
+	clauses := []string{}
+	current := []string{}
+	for _, word := range strings.Fields(text) {
+		if strings.EqualFold(word, "and") {
+			clauses = append(clauses, strings.Join(current, " "))
+			current = nil
+			continue
+		}
+		current = append(current, word)
+	}
+	return append(clauses, strings.Join(current, " "))
+}
+
+// parseClause parses a single clause of a condition
+func (ge *GameEngine) parseClause(text string) (conditionClause, error) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return conditionClause{}, fmt.Errorf("missing condition around 'and'")
+	}
+
+	if words[0] == "researched" {
+		if len(words) != 2 {
+			return conditionClause{}, fmt.Errorf("expected 'researched <technology>', got %q", text)
+		}
+		tech, exists := ge.Research.Resolve(words[1])
+		if !exists {
+			return conditionClause{}, fmt.Errorf("unknown technology %q", words[1])
+		}
+		return conditionClause{kind: clauseResearched, name: tech}, nil
+	}
+	if len(words) == 2 && words[0] == "research" && words[1] == "idle" {
+		return conditionClause{kind: clauseResearchIdle}, nil
+	}
+
+	match := comparisonPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return conditionClause{}, fmt.Errorf("expected a comparison such as 'wood >= 20', got %q", text)
+	}
+	quantity := strings.Join(strings.Fields(strings.ToLower(match[1])), " ")
+	if alias, exists := quantityAliases[quantity]; exists {
+		quantity = alias
+	}
+	op := match[2]
+	if op == "=" {
+		op = "=="
+	}
+
+	if quantity == "age" {
+		for i, age := range ge.Progress.GetAllAges() {
+			if strings.EqualFold(age, strings.TrimSpace(match[3])) {
+				return conditionClause{kind: clauseAge, op: op, value: float64(i)}, nil
+			}
+		}
+		return conditionClause{}, fmt.Errorf("unknown age %q", match[3])
+	}
+
+	if _, known := ge.quantity(quantity); !known {
+		return conditionClause{}, fmt.Errorf("unknown quantity %q", quantity)
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(match[3]), 64)
+	if err != nil {
+		return conditionClause{}, fmt.Errorf("expected a number after %s, got %q", op, match[3])
+	}
+	return conditionClause{kind: clauseQuantity, quantity: quantity, op: op, value: value}, nil
+}
+
+// quantity returns the current value of a named quantity. The caller must
+// hold the engine lock.
+func (ge *GameEngine) quantity(name string) (float64, bool) {
+	villagers := ge.Villagers.GetAll()
+
+	switch name {
+	case "food":
+		return ge.Resources.GetTotalFood(), true
+	case "population":
+		total := 0
+		for _, info := range villagers {
+			total += info.Count
+		}
+		return float64(total), true
+	case "idle":
+		total := 0
+		for _, info := range villagers {
+			total += info.Assignment["idle"]
+		}
+		return float64(total), true
+	case "capacity":
+		return float64(ge.Buildings.GetVillagerCapacity()), true
+	case "tick":
+		return float64(ge.Tick), true
+	}
+
+	if vtype, found := strings.CutPrefix(name, "idle "); found {
+		if info, exists := villagers[vtype]; exists {
+			return float64(info.Assignment["idle"]), true
+		}
+		return 0, false
+	}
+	if amount, exists := ge.Resources.GetAll()[name]; exists {
+		return amount, true
+	}
+	if count, exists := ge.Buildings.GetAll()[name]; exists {
+		return float64(count), true
+	}
+	if info, exists := villagers[name]; exists {
+		return float64(info.Count), true
+	}
+	return 0, false
+}
+
+// evaluate reports whether every clause of the condition holds. The caller
+// must hold the engine lock.
+func (c *Condition) evaluate(ge *GameEngine) bool {
+	for _, clause := range c.clauses {
+		if !clause.holds(ge) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds reports whether a single clause holds
+func (cl conditionClause) holds(ge *GameEngine) bool {
+	switch cl.kind {
+	case clauseResearched:
+		return ge.Research.IsResearched(cl.name)
+	case clauseResearchIdle:
+		current, _, _ := ge.Research.GetProgress()
+		return current == ""
+	case clauseAge:
+		return compare(float64(ge.Progress.GetCurrentAgeIndex(ge.Age)), cl.op, cl.value)
+	default:
+		value, _ := ge.quantity(cl.quantity)
+		return compare(value, cl.op, cl.value)
+	}
+}
+
+// compare applies a comparison operator
+func compare(a float64, op string, b float64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// defaultContentData is the built-in content pack shipped with the binary
+//
+//go:embed content/default.json
+var defaultContentData []byte
+
+// ContentPack defines all game content: resources, buildings, villager types,
+// technologies and ages. The built-in pack is embedded in the binary and can
+// be tuned by loading an override pack from disk.
+type ContentPack struct {
+	DefaultFood  string                 `json:"defaultFood"` // Resource that receives generic "food"
+	Resources    map[string]ResourceDef `json:"resources"`
+	Buildings    map[string]BuildingDef `json:"buildings"`
+	Villagers    map[string]VillagerDef `json:"villagers"`
+	Technologies map[string]Technology  `json:"technologies"`
+	Ages         []AgeDef               `json:"ages"`
+	Start        StartDef               `json:"start"`
+}
+
+// ResourceDef defines a gatherable resource
+type ResourceDef struct {
+	CollectionRate float64 `json:"collectionRate"`      // Base amount gathered per villager per tick
+	Food           bool    `json:"food,omitempty"`      // Whether the resource counts as food
+	FoodBonus      float64 `json:"foodBonus,omitempty"` // Extra food per villager, as a fraction of the base rate
+}
+
+// BuildingDef defines a building type
+type BuildingDef struct {
+	Cost        map[string]float64            `json:"cost"`
+	Effects     map[string]float64            `json:"effects"`
+	RateBonuses map[string]map[string]float64 `json:"rateBonuses,omitempty"` // villagerType -> resource -> bonus percentage
+	BuildTime   int                           `json:"buildTime,omitempty"`   // Builder-ticks of work to construct; 0 builds instantly
+	UpgradeFrom string                        `json:"upgradeFrom,omitempty"` // Building this tier is upgraded from; tiers can't be built directly
+}
+
+// VillagerDef defines a villager type
+type VillagerDef struct {
+	FoodCost          float64            `json:"foodCost"`
+	Tasks             []string           `json:"tasks"`                       // Resources this type can be assigned to
+	GatherMultipliers map[string]float64 `json:"gatherMultipliers,omitempty"` // Per-resource gathering rate multipliers
+}
+
+// AgeDef defines an age, what it takes to reach it and what it unlocks
+type AgeDef struct {
+	Name         string         `json:"name"`
+	Requirements AgeRequirement `json:"requirements"`
+	Unlocks      AgeUnlock      `json:"unlocks"`
+}
+
+// StartDef defines the state of a new game
+type StartDef struct {
+	Resources map[string]float64 `json:"resources"`
+	Villagers map[string]int     `json:"villagers"`
+}
+
+// DefaultContent returns a fresh copy of the built-in content pack
+func DefaultContent() *ContentPack {
+	pack := &ContentPack{}
+	if err := json.Unmarshal(defaultContentData, pack); err != nil {
+		panic(fmt.Sprintf("built-in content pack is invalid: %v", err))
+	}
+	return pack
+}
+
+// LoadContentPack loads an override pack from disk on top of the built-in pack.
+// Entries in the override's maps replace the built-in entry with the same name
+// (and new names are added); lists such as "ages" replace the built-in list.
+// The merged pack is validated and a *ContentError is returned if it has problems.
+func LoadContentPack(path string) (*ContentPack, error) {
+	pack, err := ReadContentPack(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if issues := ValidateContent(pack, path); len(issues) > 0 {
+		return nil, &ContentError{Issues: issues}
+	}
+
+	return pack, nil
+}
+
+// ReadContentPack reads and merges an override pack without validating it
+func ReadContentPack(path string) (*ContentPack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content pack: %w", err)
+	}
+
+	pack := DefaultContent()
+	if err := json.Unmarshal(data, pack); err != nil {
+		return nil, fmt.Errorf("failed to parse content pack %s: %w", path, err)
+	}
+
+	return pack, nil
+}
+
+// AgeNames returns the names of all ages in order
+func (cp *ContentPack) AgeNames() []string {
+	names := make([]string, 0, len(cp.Ages))
+	for _, age := range cp.Ages {
+		names = append(names, age.Name)
+	}
+	return names
+}
+
+// FoodSources returns the resources that count as food
+func (cp *ContentPack) FoodSources() []string {
+	sources := []string{}
+	for name, def := range cp.Resources {
+		if def.Food {
+			sources = append(sources, name)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
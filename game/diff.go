@@ -0,0 +1,304 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ValueChange is a numeric value that differs between two saves
+type ValueChange struct {
+	A     float64 `json:"a"`
+	B     float64 `json:"b"`
+	Delta float64 `json:"delta"`
+}
+
+// TextChange is a text value that differs between two saves
+type TextChange struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+// ResearchDiff describes how research differs between two saves
+type ResearchDiff struct {
+	OnlyInA []string    `json:"onlyInA,omitempty"`
+	OnlyInB []string    `json:"onlyInB,omitempty"`
+	Current *TextChange `json:"current,omitempty"`
+}
+
+// SaveDiff lists every difference between two saves. Entries that are equal
+// in both saves are left out.
+type SaveDiff struct {
+	A         string                            `json:"a"`
+	B         string                            `json:"b"`
+	Age       *TextChange                       `json:"age,omitempty"`
+	Tick      *ValueChange                      `json:"tick,omitempty"`
+	Resources map[string]ValueChange            `json:"resources,omitempty"`
+	Buildings map[string]ValueChange            `json:"buildings,omitempty"`
+	Villagers map[string]map[string]ValueChange `json:"villagers,omitempty"` // villager type -> "count" or task -> change
+	Research  *ResearchDiff                     `json:"research,omitempty"`
+	Stats     map[string]ValueChange            `json:"stats,omitempty"` // e.g. "resourcesGathered.wood"
+}
+
+// diffTolerance ignores floating point noise when comparing amounts
+const diffTolerance = 1e-9
+
+// ReadSave reads a save by name from the save directory, or from a path if
+// the argument names an existing file, upgrading it to the current version
+func ReadSave(nameOrPath string) (*GameSave, error) {
+	path := nameOrPath
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(SaveDir(), nameOrPath+".json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save file: %w", err)
+	}
+	return DecodeSave(data)
+}
+
+// DiffSaveFiles reads two saves by name or path and compares them
+func DiffSaveFiles(nameA, nameB string) (*SaveDiff, error) {
+	a, err := ReadSave(nameA)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", nameA, err)
+	}
+	b, err := ReadSave(nameB)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", nameB, err)
+	}
+	return DiffSaves(nameA, a, nameB, b), nil
+}
+
+// DiffSaves compares two saves; deltas are the change from a to b
+func DiffSaves(nameA string, a *GameSave, nameB string, b *GameSave) *SaveDiff {
+	diff := &SaveDiff{
+		A:         nameA,
+		B:         nameB,
+		Resources: diffAmounts(a.Resources, b.Resources),
+		Buildings: diffAmounts(intAmounts(a.Buildings), intAmounts(b.Buildings)),
+		Villagers: make(map[string]map[string]ValueChange),
+		Stats:     make(map[string]ValueChange),
+	}
+
+	if a.Age != b.Age {
+		diff.Age = &TextChange{A: a.Age, B: b.Age}
+	}
+	if a.Tick != b.Tick {
+		diff.Tick = &ValueChange{A: float64(a.Tick), B: float64(b.Tick), Delta: float64(b.Tick - a.Tick)}
+	}
+
+	// Villager counts and assignments, per type
+	for _, vtype := range unionKeys(villagerKeys(a.Villagers), villagerKeys(b.Villagers)) {
+		infoA, infoB := a.Villagers[vtype], b.Villagers[vtype]
+		amountsA := intAmounts(infoA.Assignment)
+		amountsA["count"] = float64(infoA.Count)
+		amountsB := intAmounts(infoB.Assignment)
+		amountsB["count"] = float64(infoB.Count)
+		if changes := diffAmounts(amountsA, amountsB); len(changes) > 0 {
+			diff.Villagers[vtype] = changes
+		}
+	}
+
+	diff.Research = diffResearch(a.Research, b.Research)
+
+	statsA, statsB := flattenStats(a.Stats), flattenStats(b.Stats)
+	diff.Stats = diffAmounts(statsA, statsB)
+
+	return diff
+}
+
+// Empty reports whether the two saves had no differences
+func (d *SaveDiff) Empty() bool {
+	return d.Age == nil && d.Tick == nil && len(d.Resources) == 0 && len(d.Buildings) == 0 &&
+		len(d.Villagers) == 0 && d.Research == nil && len(d.Stats) == 0
+}
+
+// Lines renders the diff as human-readable text, one difference per line
+func (d *SaveDiff) Lines() []string {
+	lines := []string{fmt.Sprintf("=== %s -> %s ===", d.A, d.B)}
+	if d.Empty() {
+		return append(lines, "No differences")
+	}
+
+	if d.Age != nil {
+		lines = append(lines, fmt.Sprintf("Age: %s -> %s", d.Age.A, d.Age.B))
+	}
+	if d.Tick != nil {
+		lines = append(lines, "Tick: "+formatChange(*d.Tick))
+	}
+	lines = appendSection(lines, "Resources", "", d.Resources)
+	lines = appendSection(lines, "Buildings", "", d.Buildings)
+	if len(d.Villagers) > 0 {
+		lines = append(lines, "Villagers:")
+		for _, vtype := range sortedKeys(d.Villagers) {
+			lines = appendSection(lines, "", vtype+" ", d.Villagers[vtype])
+		}
+	}
+	if d.Research != nil {
+		lines = append(lines, "Research:")
+		if len(d.Research.OnlyInA) > 0 {
+			lines = append(lines, "  only in "+d.A+": "+strings.Join(d.Research.OnlyInA, ", "))
+		}
+		if len(d.Research.OnlyInB) > 0 {
+			lines = append(lines, "  only in "+d.B+": "+strings.Join(d.Research.OnlyInB, ", "))
+		}
+		if d.Research.Current != nil {
+			lines = append(lines, fmt.Sprintf("  researching: %s -> %s",
+				orNone(d.Research.Current.A), orNone(d.Research.Current.B)))
+		}
+	}
+	lines = appendSection(lines, "Stats", "", d.Stats)
+
+	return lines
+}
+
+// appendSection renders a map of changes under an optional heading
+func appendSection(lines []string, heading, prefix string, changes map[string]ValueChange) []string {
+	if len(changes) == 0 {
+		return lines
+	}
+	if heading != "" {
+		lines = append(lines, heading+":")
+	}
+	for _, name := range sortedKeys(changes) {
+		lines = append(lines, fmt.Sprintf("  %s%s: %s", prefix, name, formatChange(changes[name])))
+	}
+	return lines
+}
+
+// formatChange renders a change as "a -> b (+delta)"
+func formatChange(change ValueChange) string {
+	return fmt.Sprintf("%g -> %g (%+g)", round1(change.A), round1(change.B), round1(change.Delta))
+}
+
+// round1 rounds to one decimal place for display
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// diffAmounts returns the entries whose amounts differ; missing entries count as zero
+func diffAmounts(a, b map[string]float64) map[string]ValueChange {
+	changes := make(map[string]ValueChange)
+	for _, key := range unionKeys(a, b) {
+		delta := b[key] - a[key]
+		if delta > diffTolerance || delta < -diffTolerance {
+			changes[key] = ValueChange{A: a[key], B: b[key], Delta: delta}
+		}
+	}
+	return changes
+}
+
+// diffResearch compares research state, returning nil when it is the same
+func diffResearch(a, b *ResearchState) *ResearchDiff {
+	if a == nil {
+		a = &ResearchState{}
+	}
+	if b == nil {
+		b = &ResearchState{}
+	}
+
+	inA := make(map[string]bool)
+	for _, tech := range a.Researched {
+		inA[tech] = true
+	}
+	inB := make(map[string]bool)
+	for _, tech := range b.Researched {
+		inB[tech] = true
+	}
+
+	diff := &ResearchDiff{}
+	for _, tech := range a.Researched {
+		if !inB[tech] {
+			diff.OnlyInA = append(diff.OnlyInA, tech)
+		}
+	}
+	for _, tech := range b.Researched {
+		if !inA[tech] {
+			diff.OnlyInB = append(diff.OnlyInB, tech)
+		}
+	}
+	sort.Strings(diff.OnlyInA)
+	sort.Strings(diff.OnlyInB)
+	if a.Current != b.Current {
+		diff.Current = &TextChange{A: a.Current, B: b.Current}
+	}
+
+	if len(diff.OnlyInA) == 0 && len(diff.OnlyInB) == 0 && diff.Current == nil {
+		return nil
+	}
+	return diff
+}
+
+// flattenStats turns the numeric statistics into "section.key" entries
+func flattenStats(stats *GameStats) map[string]float64 {
+	flat := make(map[string]float64)
+	if stats == nil {
+		return flat
+	}
+	for resource, amount := range stats.ResourcesGathered {
+		flat["resourcesGathered."+resource] = amount
+	}
+	for building, count := range stats.BuildingsBuilt {
+		flat["buildingsBuilt."+building] = float64(count)
+	}
+	for villagerType, count := range stats.VillagersRecruited {
+		flat["villagersRecruited."+villagerType] = float64(count)
+	}
+	flat["events"] = float64(len(stats.Events))
+	flat["agesReached"] = float64(len(stats.AgesReached))
+	flat["unverifiedLoads"] = float64(stats.UnverifiedLoads)
+	return flat
+}
+
+func intAmounts[K comparable](counts map[K]int) map[K]float64 {
+	amounts := make(map[K]float64, len(counts))
+	for key, count := range counts {
+		amounts[key] = float64(count)
+	}
+	return amounts
+}
+
+func villagerKeys(villagers map[string]VillagerInfo) map[string]float64 {
+	keys := make(map[string]float64, len(villagers))
+	for vtype := range villagers {
+		keys[vtype] = 0
+	}
+	return keys
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]float64) []string {
+	seen := make(map[string]bool)
+	keys := []string{}
+	for _, m := range []map[string]float64{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
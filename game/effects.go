@@ -0,0 +1,66 @@
+package game
+
+// EffectType identifies what a technology effect does
+type EffectType string
+
+const (
+	// EffectProductionMultiplier raises production of a resource by Value (0.2 = +20%)
+	EffectProductionMultiplier EffectType = "production_multiplier"
+	// EffectFlatBonus adds Value of a resource every tick
+	EffectFlatBonus EffectType = "flat_bonus"
+	// EffectUnlockBuilding makes Building available regardless of age
+	EffectUnlockBuilding EffectType = "unlock_building"
+	// EffectUnlockVillager makes Villager available for recruitment regardless of age
+	EffectUnlockVillager EffectType = "unlock_villager"
+	// EffectCapacity increases villager capacity by Value
+	EffectCapacity EffectType = "capacity"
+	// EffectCostReduction lowers the cost of Building by Value (0.1 = -10%)
+	EffectCostReduction EffectType = "cost_reduction"
+)
+
+// AllTargets can be used as the resource or building of an effect to apply it to everything
+const AllTargets = "all"
+
+// TechEffect is a single typed effect granted by researching a technology
+type TechEffect struct {
+	Type     EffectType `json:"type"`
+	Resource string     `json:"resource,omitempty"`
+	Building string     `json:"building,omitempty"`
+	Villager string     `json:"villager,omitempty"`
+	Value    float64    `json:"value,omitempty"`
+}
+
+// ResearchModifiers is the combined effect of every researched technology.
+// The engine recomputes it whenever research completes and hands it to the
+// managers that consult it on every tick.
+type ResearchModifiers struct {
+	ProductionBonuses map[string]float64 // resource (or "all") -> bonus fraction
+	FlatBonuses       map[string]float64 // resource -> amount per tick
+	CostReductions    map[string]float64 // building (or "all") -> reduction fraction
+	CapacityBonus     int
+	UnlockedBuildings []string
+	UnlockedVillagers []string
+}
+
+// NewResearchModifiers creates an empty set of modifiers
+func NewResearchModifiers() *ResearchModifiers {
+	return &ResearchModifiers{
+		ProductionBonuses: make(map[string]float64),
+		FlatBonuses:       make(map[string]float64),
+		CostReductions:    make(map[string]float64),
+	}
+}
+
+// ProductionMultiplier returns the factor applied to production of a resource
+func (m *ResearchModifiers) ProductionMultiplier(resource string) float64 {
+	return (1 + m.ProductionBonuses[AllTargets]) * (1 + m.ProductionBonuses[resource])
+}
+
+// CostMultiplier returns the factor applied to the cost of a building
+func (m *ResearchModifiers) CostMultiplier(building string) float64 {
+	multiplier := (1 - m.CostReductions[AllTargets]) * (1 - m.CostReductions[building])
+	if multiplier < 0 {
+		return 0
+	}
+	return multiplier
+}
@@ -2,13 +2,23 @@ package game
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// GameEngine represents the main game state and logic
+// GameEngine represents the main game state and logic.
+//
+// The engine owns all game state. Every mutation (ticks, commands, loading)
+// and every snapshot taken for the UI happens while holding mu, so callers
+// outside the engine must go through the locked entry points (ProcessCommand,
+// GetGameState, SaveGame, LoadGame) instead of touching the managers directly.
 type GameEngine struct {
 	Display   DisplayInterface
-	Running   bool
+	Content   *ContentPack // Definitions of all buildings, technologies, ages, etc.
+	Running   atomic.Bool  // Read without the lock by the main loop and live scripts
+	CivName   string       // Name the player gave their civilization
 	Tick      int
 	Age       string
 	Resources *ResourceManager
@@ -16,15 +26,42 @@ type GameEngine struct {
 	Villagers *VillagerManager
 	Progress  *ProgressManager
 	Research  *ResearchManager
+	Rules     *RuleManager       // Automation rules checked every tick
+	Queue     *BuildQueue        // Buildings waiting to be built once affordable
+	modifiers *ResearchModifiers // Combined effect of researched technologies
 	// Library        *LibrarySystem
 	Commands       *CommandHandler
 	Stats          *GameStats
 	TickDuration   time.Duration
+	Speed          float64 // Game speed multiplier applied to BaseTickDuration
+	Paused         bool    // When paused, no ticks are processed
 	LastUpdateTime time.Time
-	RefreshRate    time.Duration // How often to refresh the UI
-	stopRefresh    chan bool     // Channel to signal stopping the UI refresh
+	// Offline progress settings
+	OfflineEfficiency  float64       // Fraction of missed ticks simulated when catching up
+	MaxOfflineDuration time.Duration // Longest absence that will be caught up
+	// Autosave settings
+	AutosaveInterval time.Duration // How often to autosave; zero disables periodic autosaves
+	AutosaveSlots    int           // Number of rotating autosave slots kept
+	RefreshRate      time.Duration // How often to refresh the UI
+	StartupScript    *Script       // Script run in the background once the game clock starts, if any
+	live             bool          // The real-time game clock is running
+	scriptCancel     chan struct{} // Closed to stop the running background script, if any
+	stopRefresh      chan bool     // Channel to signal stopping the UI refresh
+	stopTicks        chan bool     // Channel to signal stopping the tick scheduler
+	tickChanged      chan bool     // Channel to signal the tick scheduler that TickDuration changed
+	stopAutosave     chan bool     // Channel to signal stopping the autosave loop
+	sessionActive    bool          // Whether this run holds the session lock file
+	mu               sync.Mutex    // Guards all game state
 }
 
+const (
+	// BaseTickDuration is the length of a tick at 1x speed
+	BaseTickDuration = 5 * time.Second
+	// MinSpeed and MaxSpeed bound the game speed multiplier
+	MinSpeed = 0.1
+	MaxSpeed = 100.0
+)
+
 // DisplayInterface defines the interface for the UI display
 type DisplayInterface interface {
 	ShowHelp(commands map[string]string)
@@ -35,30 +72,47 @@ type DisplayInterface interface {
 	Stop()
 }
 
-// NewGameEngine creates a new game engine
+// NewGameEngine creates a new game engine using the built-in content pack
 func NewGameEngine(display DisplayInterface) *GameEngine {
+	return NewGameEngineWithContent(display, DefaultContent())
+}
+
+// NewGameEngineWithContent creates a new game engine using the given content pack
+func NewGameEngineWithContent(display DisplayInterface, content *ContentPack) *GameEngine {
 	ge := &GameEngine{
-		Display:        display,
-		Running:        false,
-		Tick:           0,
-		Age:            "Stone Age",
-		TickDuration:   5 * time.Second,
-		LastUpdateTime: time.Now(),
-		RefreshRate:    5 * time.Second, // Match refresh rate to tick duration
-		stopRefresh:    make(chan bool), // Initialize the stop channel
+		Display:            display,
+		Content:            content,
+		Tick:               0,
+		Age:                content.AgeNames()[0],
+		TickDuration:       BaseTickDuration,
+		Speed:              1,
+		LastUpdateTime:     time.Now(),
+		OfflineEfficiency:  DefaultOfflineEfficiency,
+		MaxOfflineDuration: DefaultMaxOfflineDuration,
+		AutosaveInterval:   DefaultAutosaveInterval,
+		AutosaveSlots:      DefaultAutosaveSlots,
+		RefreshRate:        5 * time.Second, // Match refresh rate to tick duration
+		stopRefresh:        make(chan bool), // Initialize the stop channel
+		stopTicks:          make(chan bool),
+		stopAutosave:       make(chan bool),
+		tickChanged:        make(chan bool, 1),
 	}
 
 	// Initialize game components
-	ge.Resources = NewResourceManager()
-	ge.Buildings = NewBuildingManager()
-	ge.Villagers = NewVillagerManager()
-	ge.Progress = NewProgressManager()
-	ge.Research = NewResearchManager()
+	ge.Resources = NewResourceManager(ge.Content)
+	ge.Buildings = NewBuildingManager(ge.Content)
+	ge.Villagers = NewVillagerManager(ge.Content)
+	ge.Progress = NewProgressManager(ge.Content)
+	ge.Research = NewResearchManager(ge.Content)
+	ge.Rules = NewRuleManager()
+	ge.Queue = NewBuildQueue()
 	// ge.Library = NewLibrarySystem()
 	ge.Stats = NewGameStats()
+	ge.Stats.AgesReached = []string{ge.Age}
 
 	// Initialize game state
 	ge.initializeGame()
+	ge.applyResearch()
 
 	// Create command handler (after initializing components)
 	ge.Commands = NewCommandHandler(ge)
@@ -69,32 +123,41 @@ func NewGameEngine(display DisplayInterface) *GameEngine {
 // initializeGame sets up the initial game state
 func (ge *GameEngine) initializeGame() {
 	// Add initial resources
-	ge.Resources.Add("food", 20)
-	ge.Resources.Add("wood", 20)
+	for resource, amount := range ge.Content.Start.Resources {
+		ge.Resources.Add(resource, amount)
+	}
 
-	// Start with one villager
-	ge.Villagers.Add("villager", 1)
+	// Add the starting villagers
+	for villagerType, count := range ge.Content.Start.Villagers {
+		ge.Villagers.Add(villagerType, count)
+	}
 }
 
 // Start initializes and starts the game engine
 func (ge *GameEngine) Start() error {
-	ge.Running = true
+	ge.Running.Store(true)
 
 	// Initialize all subsystems if they haven't been already
 	if ge.Resources == nil {
-		ge.Resources = NewResourceManager()
+		ge.Resources = NewResourceManager(ge.Content)
 	}
 	if ge.Buildings == nil {
-		ge.Buildings = NewBuildingManager()
+		ge.Buildings = NewBuildingManager(ge.Content)
 	}
 	if ge.Villagers == nil {
-		ge.Villagers = NewVillagerManager()
+		ge.Villagers = NewVillagerManager(ge.Content)
 	}
 	if ge.Progress == nil {
-		ge.Progress = NewProgressManager()
+		ge.Progress = NewProgressManager(ge.Content)
 	}
 	if ge.Research == nil {
-		ge.Research = NewResearchManager()
+		ge.Research = NewResearchManager(ge.Content)
+	}
+	if ge.Rules == nil {
+		ge.Rules = NewRuleManager()
+	}
+	if ge.Queue == nil {
+		ge.Queue = NewBuildQueue()
 	}
 	// if ge.Library == nil {
 	// 	ge.Library = NewLibrarySystem()
@@ -105,26 +168,106 @@ func (ge *GameEngine) Start() error {
 	if ge.Commands == nil {
 		ge.Commands = NewCommandHandler(ge)
 	}
+	ge.applyResearch()
 
 	ge.RefreshRate = 500 * time.Millisecond
 	ge.stopRefresh = make(chan bool)
+	ge.stopTicks = make(chan bool)
+	ge.stopAutosave = make(chan bool)
+
+	// Time only starts counting once the scheduler is running
+	ge.LastUpdateTime = time.Now()
 
-	// Start the UI refresh goroutine
+	// Mark the session as running so a crash can be recovered from next time
+	ge.mu.Lock()
+	ge.beginSession()
+	ge.live = true
+	ge.mu.Unlock()
+
+	// Start the tick scheduler, UI refresh and autosave goroutines
+	go ge.tickLoop()
 	go ge.refreshUILoop()
+	go ge.autosaveLoop()
+
+	if ge.StartupScript != nil {
+		ge.mu.Lock()
+		if err := ge.startScript(ge.StartupScript); err != nil {
+			ge.Display.ShowMessage(err.Error(), "error")
+		}
+		ge.mu.Unlock()
+	}
 
 	// Run the main game loop
 	err := ge.mainLoop()
 
-	// Signal UI to stop refreshing
+	// Signal the background loops to stop
+	ge.stopLoops()
+
+	// Return any error from the main loop
+	return err
+}
+
+// stopLoops signals the tick scheduler, UI refresh and autosave goroutines to exit
+func (ge *GameEngine) stopLoops() {
+	for _, ch := range []chan bool{ge.stopTicks, ge.stopRefresh, ge.stopAutosave} {
+		select {
+		case <-ch:
+			// Channel already closed, do nothing
+		default:
+			close(ch)
+		}
+	}
+}
+
+// tickLoop advances the game world every TickDuration, independently of the UI refresh rate
+func (ge *GameEngine) tickLoop() {
+	ge.mu.Lock()
+	ticker := time.NewTicker(ge.TickDuration)
+	ge.mu.Unlock()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ge.mu.Lock()
+			if ge.Paused {
+				// Time is frozen while paused, so nothing accumulates for resume
+				ge.LastUpdateTime = time.Now()
+			} else {
+				// Catch up on every tick that has elapsed since the last update
+				ge.advanceToNow()
+			}
+			ge.mu.Unlock()
+		case <-ge.tickChanged:
+			// The game speed changed; pick up the new tick duration
+			ge.mu.Lock()
+			ticker.Reset(ge.TickDuration)
+			ge.mu.Unlock()
+		case <-ge.stopTicks:
+			return
+		}
+	}
+}
+
+// setSpeed changes the game speed multiplier; the caller must hold the engine lock
+func (ge *GameEngine) setSpeed(speed float64) {
+	ge.Speed = speed
+	ge.TickDuration = time.Duration(float64(BaseTickDuration) / speed)
+
+	// Drop the partial tick so the new speed starts from a clean boundary
+	ge.LastUpdateTime = time.Now()
+
+	// Wake the tick scheduler without blocking if a signal is already pending
 	select {
-	case <-ge.stopRefresh:
-		// Channel already closed, do nothing
+	case ge.tickChanged <- true:
 	default:
-		close(ge.stopRefresh)
 	}
+}
 
-	// Return any error from the main loop
-	return err
+// setPaused pauses or resumes the simulation; the caller must hold the engine lock
+func (ge *GameEngine) setPaused(paused bool) {
+	ge.Paused = paused
+	ge.LastUpdateTime = time.Now()
 }
 
 // refreshUILoop updates the UI at regular intervals
@@ -146,7 +289,7 @@ func (ge *GameEngine) refreshUILoop() {
 
 // mainLoop is the main game loop
 func (ge *GameEngine) mainLoop() error {
-	for ge.Running {
+	for ge.Running.Load() {
 		// Get user command (non-blocking)
 		userInput, err := ge.Display.GetInput()
 		if err != nil {
@@ -155,12 +298,37 @@ func (ge *GameEngine) mainLoop() error {
 			continue
 		}
 
-		// Process command (ticks are now handled by the refresh loop)
-		ge.Commands.Process(userInput)
+		// Process command (ticks are handled by the tick scheduler)
+		ge.ProcessCommand(userInput)
 	}
 	return nil
 }
 
+// ProcessCommand runs a single user command while holding the engine lock,
+// returning the error it reported, if any
+func (ge *GameEngine) ProcessCommand(userInput string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.Commands.Process(userInput)
+}
+
+// advanceToNow brings the simulation up to the current wall-clock time. Short
+// delays are processed as regular ticks; longer gaps (e.g. the machine was
+// suspended) go through the offline catch-up and produce a summary.
+func (ge *GameEngine) advanceToNow() {
+	elapsed := time.Since(ge.LastUpdateTime)
+	if elapsed >= offlineThresholdTicks*ge.TickDuration {
+		ge.LastUpdateTime = time.Now()
+		if report := ge.catchUp(elapsed); report != nil {
+			ge.showOfflineReport(report)
+		}
+		return
+	}
+
+	ge.updateMultipleTicks(ge.calculateElapsedTicks())
+}
+
 // calculateElapsedTicks calculates how many ticks have passed since the last update
 func (ge *GameEngine) calculateElapsedTicks() int {
 	now := time.Now()
@@ -178,25 +346,124 @@ func (ge *GameEngine) calculateElapsedTicks() int {
 	return elapsedTicks
 }
 
+// applyResearch recomputes the combined effect of all researched technologies
+// and hands it to the managers that consult it every tick
+func (ge *GameEngine) applyResearch() {
+	ge.modifiers = ge.Research.GetModifiers()
+	ge.Buildings.ApplyResearch(ge.modifiers)
+	ge.Villagers.ApplyResearch(ge.modifiers)
+}
+
+// recordBuilt tracks a finished building in the statistics. The caller must
+// hold the engine lock.
+func (ge *GameEngine) recordBuilt(building string) {
+	ge.Stats.AddEvent(ge.Tick, "building_built", "Built a new "+building)
+	ge.Stats.AddBuildingBuilt(building)
+}
+
+// recordUpgraded tracks a finished upgrade in the statistics. The caller
+// must hold the engine lock.
+func (ge *GameEngine) recordUpgraded(from, to string) {
+	ge.Stats.AddEvent(ge.Tick, "building_upgraded", "Upgraded a "+from+" to a "+to)
+	ge.Stats.AddBuildingBuilt(to)
+}
+
+// startQueuedResearch starts the technology at the front of the research
+// queue if nothing is being researched and it can be started, e.g. once
+// there are knowledge points to pay for it. The caller must hold the engine
+// lock.
+func (ge *GameEngine) startQueuedResearch() bool {
+	for {
+		next, queued := ge.Research.NextQueued()
+		if !queued {
+			return false
+		}
+		if current, _, _ := ge.Research.GetProgress(); current != "" {
+			return false
+		}
+
+		// Drop technologies that can no longer be started, e.g. after loading
+		// a save, so they don't hold up the rest of the queue
+		if ge.Research.IsResearched(next) {
+			ge.Research.PopQueued()
+			ge.Display.ShowMessage("Removed "+next+" from the research queue; it has already been researched", "info")
+			continue
+		}
+		if reason := ge.Research.unavailableReason(next, ge.Age); reason != "" {
+			removed := ge.Research.Dequeue(next)
+			ge.Display.ShowMessage("Removed from the research queue: "+strings.Join(removed, ", ")+" ("+reason+")", "warning")
+			continue
+		}
+
+		if ge.Commands.startResearch(next) != nil {
+			return false
+		}
+
+		ge.Research.PopQueued()
+		ge.Display.ShowMessage("Started researching "+next, "success")
+		return true
+	}
+}
+
 // updateSingleTick processes a single tick of game time
 func (ge *GameEngine) updateSingleTick() {
-	ge.Tick++
+	ge.updateTicks(1)
+}
+
+// updateTicks processes the given number of ticks as a single simulation step,
+// scaling all production, consumption and research by the tick count.
+// It returns the amount of food consumed during the step.
+func (ge *GameEngine) updateTicks(ticks int) float64 {
+	ge.Tick += ticks
 
 	// Update resources based on villagers and track statistics
-	ge.Villagers.CollectResourcesAndTrack(ge.Resources, ge.Stats, ge.Buildings)
+	foodConsumed := ge.Villagers.CollectResourcesAndTrack(ge.Resources, ge.Stats, ge.Buildings, ticks)
 
 	// Update buildings
-	ge.Buildings.Update(ge.Resources)
+	ge.Buildings.Update(ge.Resources, ticks)
+
+	// Builders work on buildings under construction; finished buildings
+	// take effect from the next tick
+	for _, site := range ge.Buildings.Construct(float64(ge.Villagers.GetAssigned(ConstructionTask) * ticks)) {
+		if site.Upgrades != "" {
+			ge.Display.ShowMessage("Finished upgrading a "+site.Upgrades+" to a "+site.Building, "success")
+			ge.recordUpgraded(site.Upgrades, site.Building)
+			continue
+		}
+		ge.Display.ShowMessage("Finished building a new "+site.Building, "success")
+		ge.recordBuilt(site.Building)
+	}
+
+	// Add flat bonuses from research
+	for resource, amount := range ge.modifiers.FlatBonuses {
+		ge.Resources.Add(resource, amount*float64(ticks))
+		ge.Stats.AddResourceGathered(resource, amount*float64(ticks))
+	}
 
 	// Update research if there's an active research project
-	if techName, completed := ge.Research.ContinueResearch(ge.Resources.Get("knowledge") * 0.1); completed {
+	knowledge := ge.Resources.Get("knowledge") * 0.1 * float64(ticks)
+	for {
+		techName, completed, surplus := ge.Research.ContinueResearch(knowledge)
+		if !completed {
+			break
+		}
 		ge.Display.ShowMessage("Research completed: "+techName, "success")
 		ge.Stats.AddEvent(ge.Tick, "research_completed", "Completed research on "+techName)
 
-		// Apply any immediate effects from the research
-		// For now, this is just handled by the production rate calculation
+		// The new technology's effects apply from the next tick on
+		ge.applyResearch()
+
+		// Knowledge beyond the technology's cost goes to the next queued
+		// technology, so batched steps research as much as single ticks
+		if surplus <= 0 || !ge.startQueuedResearch() {
+			break
+		}
+		knowledge = surplus
 	}
 
+	// Move on to the next queued technology once research is idle
+	ge.startQueuedResearch()
+
 	// Check for age progression
 	newAge := ge.Progress.CheckAdvancement(ge.Resources, ge.Buildings, ge.Age)
 	if newAge != "" && newAge != ge.Age {
@@ -207,39 +474,63 @@ func (ge *GameEngine) updateSingleTick() {
 		ge.Stats.AddEvent(ge.Tick, "age_advancement", "Advanced to "+newAge)
 		ge.Stats.AddAgeReached(newAge)
 	}
+
+	// Build queued orders that have become affordable, then let the
+	// player's automation rules react to the new state
+	ge.processBuildQueue()
+	ge.applyRules()
+
+	return foodConsumed
 }
 
-// updateMultipleTicks processes multiple ticks at once
-func (ge *GameEngine) updateMultipleTicks(tickCount int) {
-	// For large tick counts (e.g. after long absence), limit to a reasonable number
-	// to prevent excessive processing and resource accumulation
-	maxTicksAtOnce := 1000 // Process at most 1000 ticks at once
-	if tickCount > maxTicksAtOnce {
-		ge.Display.ShowMessage(fmt.Sprintf("Processing %d ticks (capped from %d)...", maxTicksAtOnce, tickCount), "info")
-		tickCount = maxTicksAtOnce
-	} else if tickCount > 10 {
+// updateMultipleTicks processes multiple ticks at once and returns the food consumed.
+// Large tick counts (e.g. after a long absence) are folded into batched steps
+// of up to maxTicksPerStep ticks instead of being simulated one by one.
+func (ge *GameEngine) updateMultipleTicks(tickCount int) float64 {
+	if tickCount > 10 {
 		ge.Display.ShowMessage(fmt.Sprintf("Processing %d ticks...", tickCount), "info")
 	}
 
-	for i := 0; i < tickCount; i++ {
-		ge.updateSingleTick()
+	ticksPerStep := (tickCount + maxTickSteps - 1) / maxTickSteps
+	if ticksPerStep < 1 {
+		ticksPerStep = 1
+	}
+	if ticksPerStep > maxTicksPerStep {
+		ticksPerStep = maxTicksPerStep
+	}
+
+	foodConsumed := 0.0
+	for remaining := tickCount; remaining > 0; remaining -= ticksPerStep {
+		ticks := ticksPerStep
+		if remaining < ticks {
+			ticks = remaining
+		}
+		foodConsumed += ge.updateTicks(ticks)
 	}
+	return foodConsumed
 }
 
 // Quit quits the game
 func (ge *GameEngine) Quit() {
 	ge.Display.ShowMessage("Goodbye! Thanks for playing CivIdleCli!", "warning")
 
-	// Signal the refresh loop to stop
-	close(ge.stopRefresh)
+	// Signal the tick scheduler and refresh loop to stop
+	ge.stopLoops()
 
-	// Sleep briefly to allow the message to be displayed
-	time.Sleep(500 * time.Millisecond)
+	// Save progress and release the session lock
+	if err := ge.shutdown(); err != nil {
+		ge.Display.ShowMessage("Autosave failed: "+err.Error(), "error")
+	}
 
-	// Stop the display (which stops the tview application)
+	ge.Running.Store(false)
+
+	// Stop the display (which stops the tview application) after a short
+	// pause to let the message be seen. Quit runs with the engine lock held,
+	// so the pause happens elsewhere rather than freezing the UI.
 	if stopDisplay, ok := ge.Display.(interface{ Stop() }); ok {
-		stopDisplay.Stop()
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			stopDisplay.Stop()
+		}()
 	}
-
-	ge.Running = false
 }
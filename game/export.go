@@ -0,0 +1,197 @@
+package game
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// ExportExtension is the file extension of compressed save exports
+	ExportExtension = ".civsave"
+	// exportPrefix marks an export string and the version of its encoding
+	exportPrefix = "civsave1:"
+	// maxImportSize bounds the decompressed size of an imported save
+	maxImportSize = 16 << 20
+)
+
+// ExportSave reads a save and returns it as compact, gzip-compressed JSON.
+// The signature is dropped, since it can only be checked with the local key
+// of whoever wrote the save; the checksum still travels with it.
+func ExportSave(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(SaveDir(), name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data - save file may be corrupted: %w", err)
+	}
+	delete(raw, "signature")
+	compact, err := marshalSaveMap(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal save data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := gz.Write(compact); err != nil {
+		return nil, fmt.Errorf("failed to compress save: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress save: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalSaveMap encodes a raw save compactly, keeping the header first so
+// it can still be read on its own once the save is imported
+func marshalSaveMap(raw map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		if key != "header" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if _, exists := raw["header"]; exists {
+		keys = append([]string{"header"}, keys...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(raw[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// EncodeExportString turns a compressed export into a string that can be
+// pasted into chat or a bug report
+func EncodeExportString(compressed []byte) string {
+	return exportPrefix + base64.StdEncoding.EncodeToString(compressed)
+}
+
+// DecodeExportString reverses EncodeExportString
+func DecodeExportString(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, exportPrefix) {
+		if strings.HasPrefix(s, "civsave") {
+			return nil, fmt.Errorf("export string uses an unsupported format; it may be from a newer version of the game")
+		}
+		return nil, fmt.Errorf("not an export string (expected it to start with %q)", exportPrefix)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, exportPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("export string is damaged: %w", err)
+	}
+	return compressed, nil
+}
+
+// ImportSave decompresses an export, checks it can be loaded by this version
+// of the game and writes it to the save directory under the given name.
+// Existing saves are never overwritten.
+func ImportSave(compressed []byte, name string) error {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid save name %q", name)
+	}
+
+	data, err := decompressExport(compressed)
+	if err != nil {
+		return err
+	}
+
+	// Refuse saves from newer versions or that were altered after export
+	if _, err := DecodeSave(data); err != nil {
+		return err
+	}
+	integrity, err := VerifyExport(data)
+	if err != nil {
+		return err
+	}
+	if integrity == IntegrityTampered {
+		return ErrSaveTampered
+	}
+	if integrity == IntegrityVerified {
+		if data, err = signImportedSave(data); err != nil {
+			return fmt.Errorf("failed to sign imported save: %w", err)
+		}
+	}
+
+	savePath := filepath.Join(SaveDir(), name+".json")
+	if _, err := os.Stat(savePath); err == nil {
+		return fmt.Errorf("a save named '%s' already exists", name)
+	}
+	if err := os.MkdirAll(SaveDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+
+	// Exports are compact; store the save indented like any other
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err != nil {
+		return fmt.Errorf("failed to format save data: %w", err)
+	}
+	if err := writeFileAtomic(savePath, pretty.Bytes()); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	return nil
+}
+
+// decompressExport returns the save held by a compressed export
+func decompressExport(compressed []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("export is not a compressed save: %w", err)
+	}
+	data, err := io.ReadAll(io.LimitReader(gz, maxImportSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("export is damaged: %w", err)
+	}
+	if len(data) > maxImportSize {
+		return nil, fmt.Errorf("export is too large")
+	}
+	return data, nil
+}
+
+// ReadExport reads an export from either a .civsave file or an export string
+func ReadExport(source string) ([]byte, error) {
+	if _, err := os.Stat(source); err != nil {
+		return DecodeExportString(source)
+	}
+
+	compressed, err := os.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export file: %w", err)
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(compressed), []byte(exportPrefix)) {
+		// A text file holding an export string
+		return DecodeExportString(string(compressed))
+	}
+	return compressed, nil
+}
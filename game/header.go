@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// thumbnailResources is how many resources the header thumbnail lists
+const thumbnailResources = 4
+
+// SaveHeader summarizes a save. It is written as the first field of every
+// save file so save browsers can read it without decoding the whole game.
+type SaveHeader struct {
+	CivName     string    `json:"civName,omitempty"`
+	Age         string    `json:"age"`
+	Tick        int       `json:"tick"`
+	Population  int       `json:"population"`
+	PlayTime    string    `json:"playTime"`
+	Researched  int       `json:"researched"`
+	Researching string    `json:"researching,omitempty"`
+	Thumbnail   string    `json:"thumbnail"` // Short summary of the largest stockpiles
+	SavedAt     time.Time `json:"savedAt"`
+}
+
+// NewSaveHeader builds the header summarizing a save
+func NewSaveHeader(save *GameSave) *SaveHeader {
+	header := &SaveHeader{
+		CivName:   save.CivName,
+		Age:       save.Age,
+		Tick:      save.Tick,
+		Thumbnail: resourceThumbnail(save.Resources),
+		SavedAt:   save.Timestamp,
+	}
+	for _, info := range save.Villagers {
+		header.Population += info.Count
+	}
+	if save.Stats != nil {
+		header.PlayTime = save.Stats.GetPlayTime()
+	}
+	if save.Research != nil {
+		header.Researched = len(save.Research.Researched)
+		header.Researching = save.Research.Current
+	}
+	return header
+}
+
+// resourceThumbnail lists the largest stockpiles, e.g. "wood 120 · stone 45"
+func resourceThumbnail(resources map[string]float64) string {
+	names := make([]string, 0, len(resources))
+	for name, amount := range resources {
+		if amount >= 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if resources[names[i]] != resources[names[j]] {
+			return resources[names[i]] > resources[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > thumbnailResources {
+		names = names[:thumbnailResources]
+	}
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s %.0f", name, resources[name]))
+	}
+	if len(parts) == 0 {
+		return "no resources"
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ReadSaveHeader reads the header of a save file. Only the start of the file
+// is decoded; saves written before headers existed are decoded in full to
+// build one.
+func ReadSaveHeader(path string) (*SaveHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("save file is not a JSON object")
+	}
+	if key, err := decoder.Token(); err == nil && key == "header" {
+		var header SaveHeader
+		if err := decoder.Decode(&header); err != nil {
+			return nil, fmt.Errorf("failed to read save header: %w", err)
+		}
+		return &header, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read save file: %w", err)
+	}
+	save, err := DecodeSave(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewSaveHeader(save), nil
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// LogDisplay is a DisplayInterface implementation for running without a
+// terminal UI. Messages are written as plain text lines to a writer; pass
+// io.Discard for a silent display.
+type LogDisplay struct {
+	out io.Writer
+}
+
+// Ensure LogDisplay implements DisplayInterface
+var _ DisplayInterface = (*LogDisplay)(nil)
+
+// NewLogDisplay creates a display that logs messages to the given writer
+func NewLogDisplay(out io.Writer) *LogDisplay {
+	return &LogDisplay{out: out}
+}
+
+// ShowHelp lists the available commands
+func (d *LogDisplay) ShowHelp(commands map[string]string) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d.ShowMessage(name+": "+commands[name], "info")
+	}
+}
+
+// ShowMessage writes a message prefixed with its style
+func (d *LogDisplay) ShowMessage(message string, style string) {
+	fmt.Fprintf(d.out, "[%s] %s\n", style, message)
+}
+
+// ShowAgeAdvancement logs an age advancement
+func (d *LogDisplay) ShowAgeAdvancement(newAge string) {
+	d.ShowMessage("Civilization advanced to "+newAge+"!", "success")
+}
+
+// DisplayDashboard does nothing; the state is reported once at the end of a run
+func (d *LogDisplay) DisplayDashboard(state GameState) {}
+
+// GetInput never has input ready; headless commands come from a script
+func (d *LogDisplay) GetInput() (string, error) {
+	return "", fmt.Errorf("no input in headless mode")
+}
+
+// Stop does nothing for a log display
+func (d *LogDisplay) Stop() {}
+
+// HeadlessOptions configures a headless simulation run
+type HeadlessOptions struct {
+	Ticks  int     // Number of ticks to simulate after the script has run
+	Script *Script // Optional command script; its waits are simulated
+	Load   string  // Optional save to load before running the script
+}
+
+// StateReport is the machine-readable summary printed at the end of a headless run
+type StateReport struct {
+	TicksSimulated int                     `json:"ticksSimulated"`
+	CivName        string                  `json:"civName,omitempty"`
+	Tick           int                     `json:"tick"`
+	Age            string                  `json:"age"`
+	Resources      map[string]float64      `json:"resources"`
+	TotalFood      float64                 `json:"totalFood"`
+	Rates          map[string]float64      `json:"rates"`
+	FoodRate       float64                 `json:"foodRate"`
+	Buildings      map[string]int          `json:"buildings"`
+	Construction   []ConstructionSite      `json:"construction,omitempty"`
+	Villagers      map[string]VillagerInfo `json:"villagers"`
+	VillagerCap    int                     `json:"villagerCap"`
+	Research       ResearchReport          `json:"research"`
+	Stats          *GameStats              `json:"stats"`
+}
+
+// ResearchReport describes research progress in a StateReport
+type ResearchReport struct {
+	Current    string   `json:"current,omitempty"`
+	Progress   float64  `json:"progress"`
+	Cost       float64  `json:"cost"`
+	Researched []string `json:"researched"`
+	Queue      []string `json:"queue,omitempty"`
+}
+
+// RunHeadless runs the simulation without a terminal UI: it optionally loads a
+// save, executes the command script, advances the requested number of ticks
+// and returns a report of the final state. Loaded saves aren't caught up to
+// the current time, so a run gives the same result whenever it happens.
+func (ge *GameEngine) RunHeadless(opts HeadlessOptions) (*StateReport, error) {
+	ge.Running.Store(true)
+	startTick := ge.Tick
+
+	if opts.Load != "" {
+		if err := ge.LoadGame(opts.Load); err != nil {
+			return nil, err
+		}
+		startTick = ge.Tick
+	}
+
+	if opts.Script != nil {
+		ge.mu.Lock()
+		err := ge.runScript(opts.Script)
+		ge.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("script stopped at %w", err)
+		}
+	}
+
+	if ge.Running.Load() {
+		ge.AdvanceTicks(opts.Ticks)
+	}
+
+	report := ge.Report()
+	report.TicksSimulated = report.Tick - startTick
+	return report, nil
+}
+
+// AdvanceTicks deterministically simulates the given number of ticks,
+// independent of wall-clock time
+func (ge *GameEngine) AdvanceTicks(ticks int) {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	for i := 0; i < ticks; i++ {
+		ge.updateSingleTick()
+	}
+
+	// The simulation is up to date, so a later load won't replay these ticks
+	ge.LastUpdateTime = time.Now()
+}
+
+// Report returns a machine-readable snapshot of the current game state
+func (ge *GameEngine) Report() *StateReport {
+	state := ge.GetGameState()
+
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return &StateReport{
+		CivName:      state.CivName,
+		Tick:         state.Tick,
+		Age:          state.Age,
+		Resources:    state.Resources,
+		TotalFood:    state.TotalFood,
+		Rates:        state.Rates,
+		FoodRate:     state.FoodRate,
+		Buildings:    state.Buildings,
+		Construction: state.Construction,
+		Villagers:    state.Villagers,
+		VillagerCap:  state.VillagerCap,
+		Research: ResearchReport{
+			Current:    state.Research.Current,
+			Progress:   state.Research.Progress,
+			Cost:       state.Research.Cost,
+			Researched: state.Research.Researched,
+			Queue:      state.Research.Queue,
+		},
+		Stats: ge.Stats.Clone(),
+	}
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultHistorySize is how many commands the history keeps by default
+	DefaultHistorySize = 500
+	// historyFileName is the file, in the save directory, holding the history
+	historyFileName = "command_history"
+)
+
+// CommandHistory is the list of commands the player has entered, oldest
+// first, persisted so it survives restarts. Repeated commands are kept once,
+// at their most recent position.
+type CommandHistory struct {
+	path    string
+	limit   int
+	entries []string
+}
+
+// HistoryPath returns the location of the command history file
+func HistoryPath() string {
+	return filepath.Join(SaveDir(), historyFileName)
+}
+
+// LoadCommandHistory reads the history from a file, keeping at most limit
+// entries. A missing file gives an empty history.
+func LoadCommandHistory(path string, limit int) (*CommandHistory, error) {
+	h := &CommandHistory{path: path, limit: limit}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	} else if err != nil {
+		return h, fmt.Errorf("failed to read command history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		h.add(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return h, fmt.Errorf("failed to read command history: %w", err)
+	}
+	return h, nil
+}
+
+// Add records a command and writes the history to disk
+func (h *CommandHistory) Add(command string) error {
+	if !h.add(command) {
+		return nil
+	}
+	return h.save()
+}
+
+// add records a command in memory, reporting whether it was kept
+func (h *CommandHistory) add(command string) bool {
+	command = strings.TrimSpace(command)
+	if command == "" || h.limit <= 0 {
+		return false
+	}
+
+	for i, entry := range h.entries {
+		if entry == command {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	h.entries = append(h.entries, command)
+	if len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+	return true
+}
+
+// save writes the history file
+func (h *CommandHistory) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	data := strings.Join(h.entries, "\n") + "\n"
+	if err := writeFileAtomic(h.path, []byte(data)); err != nil {
+		return fmt.Errorf("failed to write command history: %w", err)
+	}
+	return nil
+}
+
+// Len returns the number of commands in the history
+func (h *CommandHistory) Len() int {
+	return len(h.entries)
+}
+
+// Get returns the command at index i, oldest first
+func (h *CommandHistory) Get(i int) string {
+	return h.entries[i]
+}
+
+// Search looks backwards from just before index from for a command
+// containing query, returning its index or -1
+func (h *CommandHistory) Search(query string, from int) int {
+	if from > len(h.entries) {
+		from = len(h.entries)
+	}
+	for i := from - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return i
+		}
+	}
+	return -1
+}
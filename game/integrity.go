@@ -0,0 +1,202 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveIntegrity describes whether a save file's contents can be trusted
+type SaveIntegrity string
+
+const (
+	// IntegrityVerified means the checksum (and signature, if checked) match
+	IntegrityVerified SaveIntegrity = "verified"
+	// IntegrityLegacy means the save predates checksums and can't be verified
+	IntegrityLegacy SaveIntegrity = "legacy"
+	// IntegrityUnsigned means the checksum matches but a save key is
+	// configured and the save isn't signed, e.g. because it was written
+	// before the key existed; it is signed the next time it is saved
+	IntegrityUnsigned SaveIntegrity = "unsigned"
+	// IntegrityTampered means the save was modified or corrupted after it was written
+	IntegrityTampered SaveIntegrity = "tampered"
+)
+
+// checksumSaveVersion is the first schema version whose saves carry a checksum
+const checksumSaveVersion = 2
+
+// ErrSaveTampered is returned when loading a save whose checksum or signature
+// doesn't match; it can still be loaded in unverified mode
+var ErrSaveTampered = errors.New("save file has been modified or corrupted (integrity check failed)")
+
+// saveKey is the optional local key used to sign saves with an HMAC
+var saveKey []byte
+
+// SetSaveKey enables HMAC signing of saves with the given key; nil disables it
+func SetSaveKey(key []byte) {
+	saveKey = key
+}
+
+// LoadSaveKey reads the signing key from a file, creating a new random key
+// there if the file doesn't exist yet
+func LoadSaveKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("save key file %s is empty", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read save key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate save key: %w", err)
+	}
+	encoded := []byte(hex.EncodeToString(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create save key directory: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write save key: %w", err)
+	}
+	return encoded, nil
+}
+
+// canonicalSaveData returns the bytes covered by a save's checksum: the save
+// re-encoded as compact JSON with sorted keys and without the checksum and
+// signature fields, so formatting changes alone don't invalidate it
+func canonicalSaveData(raw map[string]interface{}) ([]byte, error) {
+	stripped := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		if key != "checksum" && key != "signature" {
+			stripped[key] = value
+		}
+	}
+	return json.Marshal(stripped)
+}
+
+// sealSave sets the checksum, and a signature if a key is configured, on a
+// save that is about to be written
+func sealSave(save *GameSave) error {
+	save.Checksum = ""
+	save.Signature = ""
+
+	data, err := json.Marshal(save)
+	if err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	canonical, err := canonicalSaveData(raw)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(canonical)
+	save.Checksum = hex.EncodeToString(sum[:])
+	if saveKey != nil {
+		save.Signature = signSaveData(canonical)
+	}
+	return nil
+}
+
+// signSaveData computes the HMAC of canonical save data with the local key
+func signSaveData(canonical []byte) string {
+	mac := hmac.New(sha256.New, saveKey)
+	mac.Write(canonical)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySave checks a save file's checksum and, when a key is configured,
+// its signature. A save with a matching checksum but no signature is
+// reported as unsigned rather than tampered.
+func VerifySave(data []byte) (SaveIntegrity, error) {
+	integrity, raw, err := verifyChecksum(data)
+	if err != nil || integrity != IntegrityVerified || saveKey == nil {
+		return integrity, err
+	}
+
+	signature, signed := raw["signature"].(string)
+	if !signed {
+		return IntegrityUnsigned, nil
+	}
+	canonical, err := canonicalSaveData(raw)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal([]byte(signature), []byte(signSaveData(canonical))) {
+		return IntegrityTampered, nil
+	}
+	return IntegrityVerified, nil
+}
+
+// VerifyExport checks the checksum of an exported save. Exports are never
+// signed, since the signature can only be checked with the key of whoever
+// wrote the save.
+func VerifyExport(data []byte) (SaveIntegrity, error) {
+	integrity, _, err := verifyChecksum(data)
+	return integrity, err
+}
+
+// verifyChecksum checks a save's checksum and returns the decoded save
+func verifyChecksum(data []byte) (SaveIntegrity, map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal save data - save file may be corrupted: %w", err)
+	}
+
+	checksum, hasChecksum := raw["checksum"].(string)
+	if !hasChecksum {
+		// Saves from before checksums were introduced can't be verified, but a
+		// newer save without one has had it stripped
+		version, err := saveVersion(raw)
+		if err != nil {
+			return "", nil, err
+		}
+		if version < checksumSaveVersion {
+			return IntegrityLegacy, raw, nil
+		}
+		return IntegrityTampered, raw, nil
+	}
+
+	canonical, err := canonicalSaveData(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	sum := sha256.Sum256(canonical)
+	if !hmac.Equal([]byte(checksum), []byte(hex.EncodeToString(sum[:]))) {
+		return IntegrityTampered, raw, nil
+	}
+	return IntegrityVerified, raw, nil
+}
+
+// signImportedSave signs an imported save with the local key, if one is
+// configured, so that it loads like the player's own saves. The save's
+// checksum must already have been verified.
+func signImportedSave(data []byte) ([]byte, error) {
+	if saveKey == nil {
+		return data, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data - save file may be corrupted: %w", err)
+	}
+	canonical, err := canonicalSaveData(raw)
+	if err != nil {
+		return nil, err
+	}
+	raw["signature"] = signSaveData(canonical)
+	return marshalSaveMap(raw)
+}
@@ -0,0 +1,180 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentSaveVersion is the schema version written by this build. Bump it
+// whenever the save format changes and register a migration from the
+// previous version in saveMigrations.
+const CurrentSaveVersion = 8
+
+// saveMigration upgrades a raw save by exactly one schema version
+type saveMigration func(save map[string]interface{}) error
+
+// saveMigrations holds the migration from version i to i+1 at index i.
+// Saves written before versioning was introduced count as version 0.
+var saveMigrations = []saveMigration{
+	migrateUnversionedSave,
+	migrateChecksumSave,
+	migrateHeaderSave,
+	migrateRulesSave,
+	migrateBuildQueueSave,
+	migrateResearchQueueSave,
+	migrateConstructionSave,
+	migrateUpgradesSave,
+}
+
+// DecodeSave parses save data, upgrading saves from older schema versions to
+// the current one. It fails with a clear error for saves written by a newer
+// version of the game.
+func DecodeSave(data []byte) (*GameSave, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data - save file may be corrupted: %w", err)
+	}
+
+	version, err := saveVersion(raw)
+	if err != nil {
+		return nil, err
+	}
+	if version > CurrentSaveVersion {
+		return nil, fmt.Errorf("save file is from a newer version of the game (schema version %d, this version supports up to %d)",
+			version, CurrentSaveVersion)
+	}
+
+	for ; version < CurrentSaveVersion; version++ {
+		if err := saveMigrations[version](raw); err != nil {
+			return nil, fmt.Errorf("failed to upgrade save from schema version %d: %w", version, err)
+		}
+		raw["schemaVersion"] = version + 1
+	}
+
+	// Round-trip through JSON to decode the upgraded map into a GameSave
+	upgraded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode upgraded save: %w", err)
+	}
+	var save GameSave
+	if err := json.Unmarshal(upgraded, &save); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal save data - save file may be corrupted or from an incompatible version: %w", err)
+	}
+
+	return &save, nil
+}
+
+// saveVersion reads the schema version of a raw save; a missing version is 0
+func saveVersion(raw map[string]interface{}) (int, error) {
+	value, exists := raw["schemaVersion"]
+	if !exists {
+		return 0, nil
+	}
+
+	number, ok := value.(float64)
+	if !ok || number < 0 || number != float64(int(number)) {
+		return 0, fmt.Errorf("save file has an invalid schema version: %v", value)
+	}
+	return int(number), nil
+}
+
+// migrateUnversionedSave upgrades saves from before schema versioning. Those
+// may predate speed control and research persistence, so fill in the
+// defaults that used to be patched over while loading.
+func migrateUnversionedSave(save map[string]interface{}) error {
+	if speed, ok := save["speed"].(float64); !ok || speed <= 0 {
+		save["speed"] = 1.0
+	}
+	if _, exists := save["buildings"]; !exists {
+		save["buildings"] = map[string]interface{}{}
+	}
+	if _, exists := save["villagers"]; !exists {
+		save["villagers"] = map[string]interface{}{}
+	}
+	if _, exists := save["research"]; !exists {
+		save["research"] = map[string]interface{}{"researched": []interface{}{}}
+	}
+	return nil
+}
+
+// migrateChecksumSave upgrades version 1 saves, which were written without a
+// checksum. The data is unchanged; from version 2 on a missing checksum
+// means the save has been tampered with.
+func migrateChecksumSave(save map[string]interface{}) error {
+	return nil
+}
+
+// migrateHeaderSave upgrades version 2 saves, which were written without a
+// header, by building one from the rest of the save
+func migrateHeaderSave(save map[string]interface{}) error {
+	if _, exists := save["header"]; exists {
+		return nil
+	}
+
+	data, err := json.Marshal(save)
+	if err != nil {
+		return err
+	}
+	var decoded GameSave
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	header, err := json.Marshal(NewSaveHeader(&decoded))
+	if err != nil {
+		return err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(header, &raw); err != nil {
+		return err
+	}
+	save["header"] = raw
+	return nil
+}
+
+// migrateRulesSave upgrades version 3 saves, which predate automation rules
+func migrateRulesSave(save map[string]interface{}) error {
+	setDefault(save, "rules", []interface{}{})
+	return nil
+}
+
+// migrateBuildQueueSave upgrades version 4 saves, which predate the build
+// queue, with an empty queue
+func migrateBuildQueueSave(save map[string]interface{}) error {
+	setDefault(save, "buildQueue", []interface{}{})
+	return nil
+}
+
+// migrateResearchQueueSave upgrades version 5 saves, which predate the
+// research queue, with an empty queue
+func migrateResearchQueueSave(save map[string]interface{}) error {
+	if research, ok := save["research"].(map[string]interface{}); ok {
+		setDefault(research, "queue", []interface{}{})
+	}
+	return nil
+}
+
+// migrateConstructionSave upgrades version 6 saves, from when buildings were
+// finished instantly, with no buildings under construction
+func migrateConstructionSave(save map[string]interface{}) error {
+	setDefault(save, "construction", []interface{}{})
+	return nil
+}
+
+// migrateUpgradesSave upgrades version 7 saves, which predate building
+// upgrade tiers: every construction site in them is a new building
+func migrateUpgradesSave(save map[string]interface{}) error {
+	sites, _ := save["construction"].([]interface{})
+	for _, site := range sites {
+		if site, ok := site.(map[string]interface{}); ok {
+			setDefault(site, "upgrades", "")
+		}
+	}
+	return nil
+}
+
+// setDefault sets a field of a raw save unless it is already present
+func setDefault(save map[string]interface{}, key string, value interface{}) {
+	if _, exists := save[key]; !exists {
+		save[key] = value
+	}
+}
@@ -0,0 +1,116 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOfflineEfficiency is the fraction of missed ticks simulated while away
+	DefaultOfflineEfficiency = 1.0
+	// DefaultMaxOfflineDuration is the longest absence that will be caught up
+	DefaultMaxOfflineDuration = 24 * time.Hour
+
+	// maxTickSteps is how many simulation steps a catch-up aims to take;
+	// larger gaps are folded into batched steps of several ticks each
+	maxTickSteps = 1000
+	// maxTicksPerStep bounds how many ticks are folded into one step, since
+	// builds, rules and age checks only happen between steps
+	maxTicksPerStep = 10
+	// offlineThresholdTicks is how many missed ticks count as being away
+	offlineThresholdTicks = 10
+)
+
+// OfflineReport summarizes what happened while the player was away
+type OfflineReport struct {
+	Elapsed           time.Duration
+	Ticks             int
+	ResourcesGained   map[string]float64
+	FoodConsumed      float64
+	ResearchCompleted []string
+	AgesReached       []string
+}
+
+// catchUp simulates the game time that passed while the player was away.
+// The absence is capped at MaxOfflineDuration and scaled by OfflineEfficiency.
+// It returns nil when there is nothing to simulate.
+func (ge *GameEngine) catchUp(elapsed time.Duration) *OfflineReport {
+	if ge.MaxOfflineDuration > 0 && elapsed > ge.MaxOfflineDuration {
+		elapsed = ge.MaxOfflineDuration
+	}
+
+	ticks := int(elapsed.Seconds() / ge.TickDuration.Seconds() * ge.OfflineEfficiency)
+	if ticks <= 0 {
+		return nil
+	}
+
+	// Snapshot the state we want to compare against afterwards
+	resourcesBefore := ge.Resources.GetAll()
+	researchedBefore := ge.Research.GetResearchedTechnologies()
+	agesBefore := len(ge.Stats.AgesReached)
+
+	report := &OfflineReport{
+		Elapsed:         elapsed,
+		Ticks:           ticks,
+		ResourcesGained: make(map[string]float64),
+	}
+	report.FoodConsumed = ge.updateMultipleTicks(ticks)
+
+	for resource, amount := range ge.Resources.GetAll() {
+		if gained := amount - resourcesBefore[resource]; gained > 0.05 {
+			report.ResourcesGained[resource] = gained
+		}
+	}
+	for name := range ge.Research.GetResearchedTechnologies() {
+		if _, had := researchedBefore[name]; !had {
+			report.ResearchCompleted = append(report.ResearchCompleted, name)
+		}
+	}
+	sort.Strings(report.ResearchCompleted)
+	if len(ge.Stats.AgesReached) > agesBefore {
+		report.AgesReached = append(report.AgesReached, ge.Stats.AgesReached[agesBefore:]...)
+	}
+
+	return report
+}
+
+// showOfflineReport displays a "while you were away" summary
+func (ge *GameEngine) showOfflineReport(report *OfflineReport) {
+	ge.Display.ShowMessage(fmt.Sprintf("=== While You Were Away (%s, %d ticks) ===",
+		formatDuration(report.Elapsed), report.Ticks), "highlight")
+
+	if len(report.ResourcesGained) == 0 {
+		ge.Display.ShowMessage("No resources were gathered", "info")
+	} else {
+		resources := make([]string, 0, len(report.ResourcesGained))
+		for resource := range report.ResourcesGained {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+		for _, resource := range resources {
+			ge.Display.ShowMessage(fmt.Sprintf("+%.1f %s", report.ResourcesGained[resource], resource), "success")
+		}
+	}
+
+	if report.FoodConsumed > 0 {
+		ge.Display.ShowMessage(fmt.Sprintf("Food consumed: %.1f", report.FoodConsumed), "info")
+	}
+	if len(report.ResearchCompleted) > 0 {
+		ge.Display.ShowMessage("Research completed: "+strings.Join(report.ResearchCompleted, ", "), "success")
+	}
+	if len(report.AgesReached) > 0 {
+		ge.Display.ShowMessage("Ages reached: "+strings.Join(report.AgesReached, ", "), "success")
+	}
+}
+
+// formatDuration renders a duration as hours and minutes, like GetPlayTime
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if hours == 0 && minutes == 0 {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
@@ -9,75 +9,30 @@ type ProgressManager struct {
 
 // AgeRequirement defines what's needed to advance to an age
 type AgeRequirement struct {
-	Resources map[string]float64
-	Buildings map[string]int
+	Resources map[string]float64 `json:"resources,omitempty"`
+	Buildings map[string]int     `json:"buildings,omitempty"`
 }
 
 // AgeUnlock defines what gets unlocked in an age
 type AgeUnlock struct {
-	Buildings []string
-	Resources []string
-	Villagers []string
+	Buildings []string `json:"buildings,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+	Villagers []string `json:"villagers,omitempty"`
 }
 
-// NewProgressManager creates a new progress manager
-func NewProgressManager() *ProgressManager {
+// NewProgressManager creates a new progress manager from the content pack
+func NewProgressManager(content *ContentPack) *ProgressManager {
 	pm := &ProgressManager{
-		ages: []string{
-			"Stone Age",
-			"Bronze Age",
-			"Iron Age",
-			"Medieval Age",
-			"Renaissance Age",
-			"Industrial Age",
-			"Modern Age",
-		},
-		ageRequirements: map[string]AgeRequirement{
-			"Bronze Age": {
-				Resources: map[string]float64{"stone": 50, "food": 100},
-				Buildings: map[string]int{"hut": 3, "farm": 2},
-			},
-			"Iron Age": {
-				Resources: map[string]float64{"stone": 100, "wood": 150, "knowledge": 20},
-				Buildings: map[string]int{"mine": 2, "lumber_mill": 2},
-			},
-			"Medieval Age": {
-				Resources: map[string]float64{"stone": 200, "wood": 250, "gold": 50, "knowledge": 50},
-				Buildings: map[string]int{"market": 1, "library": 1},
-			},
-			"Renaissance Age": {
-				Resources: map[string]float64{"gold": 150, "knowledge": 100},
-				Buildings: map[string]int{"library": 3, "market": 2},
-			},
-			"Industrial Age": {
-				Resources: map[string]float64{"gold": 300, "knowledge": 200},
-				Buildings: map[string]int{"library": 5, "market": 4},
-			},
-			"Modern Age": {
-				Resources: map[string]float64{"gold": 500, "knowledge": 400},
-				Buildings: map[string]int{"library": 8, "market": 6},
-			},
-		},
-		ageUnlocks: map[string]AgeUnlock{
-			"Stone Age": {
-				Buildings: []string{"hut", "farm"},
-				Resources: []string{"food", "wood"},
-			},
-			"Bronze Age": {
-				Buildings: []string{"lumber_mill", "mine"},
-				Resources: []string{"stone"},
-			},
-			"Iron Age": {
-				Buildings: []string{"market", "library"},
-				Resources: []string{"gold", "knowledge"},
-			},
-			"Medieval Age": {
-				Villagers: []string{"scholar"},
-			},
-			"Renaissance Age": {},
-			"Industrial Age":  {},
-			"Modern Age":      {},
-		},
+		ages:            content.AgeNames(),
+		ageRequirements: make(map[string]AgeRequirement),
+		ageUnlocks:      make(map[string]AgeUnlock),
+	}
+	for i, age := range content.Ages {
+		// The first age is where every game starts, so it has no requirements
+		if i > 0 {
+			pm.ageRequirements[age.Name] = age.Requirements
+		}
+		pm.ageUnlocks[age.Name] = age.Unlocks
 	}
 	return pm
 }
@@ -121,9 +76,9 @@ func (pm *ProgressManager) CheckAdvancement(resources *ResourceManager, building
 		}
 	}
 
-	// Check building requirements
+	// Check building requirements; upgraded buildings still count
 	for building, count := range requirements.Buildings {
-		if buildings.GetCount(building) < count {
+		if buildings.CountWithUpgrades(building) < count {
 			return currentAge
 		}
 	}
@@ -0,0 +1,162 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildOrder is a request in the build queue for one or more buildings of
+// the same type
+type BuildOrder struct {
+	ID       int    `json:"id"`
+	Building string `json:"building"`
+	Count    int    `json:"count"` // Buildings ordered
+	Built    int    `json:"built"` // Buildings paid for so far; they may still be under construction
+
+	blocked string // Why the order is waiting, once that has been reported
+}
+
+// Remaining returns how many buildings of the order are still to be built
+func (o *BuildOrder) Remaining() int {
+	return o.Count - o.Built
+}
+
+// BuildQueue holds build orders that are carried out in order, as soon as
+// their costs can be paid; buildings that take time to build then go to
+// construction. Resources aren't set aside: the order at the
+// front waits until the stockpile covers it, and everything behind it waits
+// its turn.
+type BuildQueue struct {
+	orders []*BuildOrder
+	nextID int
+}
+
+// NewBuildQueue creates an empty build queue
+func NewBuildQueue() *BuildQueue {
+	return &BuildQueue{nextID: 1}
+}
+
+// Add appends an order for count buildings and returns it
+func (bq *BuildQueue) Add(building string, count int) *BuildOrder {
+	order := &BuildOrder{ID: bq.nextID, Building: building, Count: count}
+	bq.nextID++
+	bq.orders = append(bq.orders, order)
+	return order
+}
+
+// Cancel removes the order with the given ID, returning it
+func (bq *BuildQueue) Cancel(id int) (*BuildOrder, bool) {
+	for i, order := range bq.orders {
+		if order.ID == id {
+			bq.orders = append(bq.orders[:i], bq.orders[i+1:]...)
+			return order, true
+		}
+	}
+	return nil, false
+}
+
+// Move puts the order with the given ID at a position in the queue,
+// counting from 1. Positions past the end move it to the back.
+func (bq *BuildQueue) Move(id, position int) bool {
+	order, exists := bq.Cancel(id)
+	if !exists {
+		return false
+	}
+	index := min(max(position-1, 0), len(bq.orders))
+	bq.orders = append(bq.orders[:index], append([]*BuildOrder{order}, bq.orders[index:]...)...)
+	return true
+}
+
+// GetAll returns copies of the orders, front of the queue first
+func (bq *BuildQueue) GetAll() []BuildOrder {
+	state := make([]BuildOrder, 0, len(bq.orders))
+	for _, order := range bq.orders {
+		state = append(state, *order)
+	}
+	return state
+}
+
+// restoreBuildQueue replaces the queue with the orders from a save, dropping
+// orders for buildings the content pack doesn't define. The caller must hold
+// the engine lock.
+func (ge *GameEngine) restoreBuildQueue(saved []BuildOrder) {
+	ge.Queue = NewBuildQueue()
+	buildings := ge.Buildings.GetAll()
+	for _, s := range saved {
+		if _, exists := buildings[s.Building]; !exists || s.Remaining() <= 0 {
+			ge.Display.ShowMessage(fmt.Sprintf("Ignoring invalid build order #%d in save", s.ID), "warning")
+			continue
+		}
+		order := s
+		ge.Queue.orders = append(ge.Queue.orders, &order)
+		if s.ID >= ge.Queue.nextID {
+			ge.Queue.nextID = s.ID + 1
+		}
+	}
+}
+
+// processBuildQueue builds as much of the queue as can be paid for, front
+// first, stopping at the first building that can't be built yet. Why the
+// queue is waiting is reported once, and orders for buildings that can never
+// be built are dropped. The caller must hold the engine lock.
+func (ge *GameEngine) processBuildQueue() {
+	for len(ge.Queue.orders) > 0 {
+		order := ge.Queue.orders[0]
+		if !ge.canEverBuild(order.Building) {
+			ge.Queue.orders = ge.Queue.orders[1:]
+			ge.Display.ShowMessage(fmt.Sprintf("Dropped queue order #%d: %s can never be built", order.ID, order.Building), "warning")
+			continue
+		}
+
+		site, err := ge.Commands.build(order.Building)
+		if err != nil {
+			if order.blocked != err.Error() {
+				order.blocked = err.Error()
+				ge.Display.ShowMessage(fmt.Sprintf("Build queue waiting on order #%d: %s", order.ID, order.blocked), "info")
+			}
+			return
+		}
+		order.blocked = ""
+		order.Built++
+		verb := "Built a new"
+		if site != nil {
+			verb = "Started building a"
+		}
+		ge.Display.ShowMessage(fmt.Sprintf("%s %s (queue order #%d, %d/%d)",
+			verb, order.Building, order.ID, order.Built, order.Count), "success")
+
+		if order.Remaining() == 0 {
+			ge.Queue.orders = ge.Queue.orders[1:]
+		}
+	}
+}
+
+// canEverBuild reports whether a building can be built directly, now or
+// later: it isn't an upgrade tier and some age or technology unlocks it
+func (ge *GameEngine) canEverBuild(building string) bool {
+	if ge.Buildings.GetUpgradeBase(building) != "" {
+		return false
+	}
+	for _, age := range ge.Progress.GetAllAges() {
+		if containsFold(ge.Progress.GetUnlocks(age).Buildings, building) {
+			return true
+		}
+	}
+	for _, tech := range ge.Research.GetAllTechnologies() {
+		for _, effect := range tech.Effects {
+			if effect.Type == EffectUnlockBuilding && effect.Building == building {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describeCost renders a cost such as "10 wood, 5 stone"
+func describeCost(cost map[string]float64) string {
+	parts := []string{}
+	for _, resource := range sortedKeys(cost) {
+		parts = append(parts, fmt.Sprintf("%.0f %s", cost[resource], resource))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArgKind is the type of value a command argument accepts
+type ArgKind int
+
+const (
+	// ArgWord is a single word
+	ArgWord ArgKind = iota
+	// ArgCount is a positive whole number
+	ArgCount
+	// ArgChoice is one of a fixed set of words
+	ArgChoice
+	// ArgRest takes all remaining words; it must be the last argument
+	ArgRest
+)
+
+// ArgSpec describes one argument of a command
+type ArgSpec struct {
+	Name        string
+	Kind        ArgKind
+	Optional    bool
+	Choices     []string     // Accepted values for ArgChoice
+	Complete    ArgCompleter // Suggests values for tab completion; defaults to Choices
+	Description string
+}
+
+// CommandFunc runs a command with arguments already checked against its
+// schema. A returned error is shown to the player as-is.
+type CommandFunc func(ch *CommandHandler, args []string) error
+
+// Command is a console command and everything needed to dispatch and
+// document it
+type Command struct {
+	Name    string
+	Aliases []string
+	Args    []ArgSpec
+	Summary string // One line shown in the command list
+	Help    string // Longer explanation shown by 'help <command>'
+	Handler CommandFunc
+}
+
+// Usage renders the command's syntax, e.g. "assign <villager_type> <resource> <count>"
+func (c *Command) Usage() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		if arg.Optional {
+			parts = append(parts, "["+arg.Name+"]")
+		} else {
+			parts = append(parts, "<"+arg.Name+">")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// CheckArgs validates arguments against the command's schema
+func (c *Command) CheckArgs(args []string) error {
+	required := 0
+	for _, arg := range c.Args {
+		if !arg.Optional {
+			required++
+		}
+	}
+	hasRest := len(c.Args) > 0 && c.Args[len(c.Args)-1].Kind == ArgRest
+	if len(args) < required || (!hasRest && len(args) > len(c.Args)) {
+		return fmt.Errorf("Usage: %s", c.Usage())
+	}
+
+	for i, value := range args {
+		if i >= len(c.Args) {
+			break
+		}
+		spec := c.Args[i]
+		switch spec.Kind {
+		case ArgCount:
+			if count, err := strconv.Atoi(value); err != nil || count <= 0 {
+				return fmt.Errorf("%s must be a positive number", capitalize(spec.Name))
+			}
+		case ArgChoice:
+			if !containsFold(spec.Choices, value) {
+				return fmt.Errorf("Usage: %s", c.Usage())
+			}
+		}
+	}
+	return nil
+}
+
+// CommandRegistry holds the commands the console understands, by name and alias
+type CommandRegistry struct {
+	commands map[string]*Command
+	aliases  map[string]string
+}
+
+// NewCommandRegistry creates an empty command registry
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{
+		commands: make(map[string]*Command),
+		aliases:  make(map[string]string),
+	}
+}
+
+// Register adds a command. Names and aliases are case-insensitive and must
+// not clash with those already registered.
+func (r *CommandRegistry) Register(cmd *Command) error {
+	if cmd.Name == "" || cmd.Handler == nil {
+		return fmt.Errorf("command must have a name and a handler")
+	}
+	for i, arg := range cmd.Args {
+		if arg.Kind == ArgRest && i != len(cmd.Args)-1 {
+			return fmt.Errorf("command %s: argument %s takes the remaining words and must be last", cmd.Name, arg.Name)
+		}
+	}
+
+	cmd.Name = strings.ToLower(cmd.Name)
+	for i, alias := range cmd.Aliases {
+		cmd.Aliases[i] = strings.ToLower(alias)
+	}
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, exists := r.Lookup(name); exists {
+			return fmt.Errorf("command %s is already registered", name)
+		}
+	}
+
+	r.commands[cmd.Name] = cmd
+	for _, alias := range cmd.Aliases {
+		r.aliases[alias] = cmd.Name
+	}
+	return nil
+}
+
+// Lookup finds a command by name or alias
+func (r *CommandRegistry) Lookup(name string) (*Command, bool) {
+	name = strings.ToLower(name)
+	if target, isAlias := r.aliases[name]; isAlias {
+		name = target
+	}
+	cmd, exists := r.commands[name]
+	return cmd, exists
+}
+
+// Commands returns all registered commands sorted by name
+func (r *CommandRegistry) Commands() []*Command {
+	commands := make([]*Command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		commands = append(commands, cmd)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+	return commands
+}
+
+// Summaries maps each command's name to its one-line summary and usage, for the
+// command list shown by 'help'
+func (r *CommandRegistry) Summaries() map[string]string {
+	summaries := make(map[string]string, len(r.commands))
+	for _, cmd := range r.commands {
+		summaries[cmd.Name] = cmd.Summary
+		if len(cmd.Args) > 0 {
+			summaries[cmd.Name] += " (" + cmd.Usage() + ")"
+		}
+	}
+	return summaries
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -1,99 +1,52 @@
 package game
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // ResearchManager handles technology research and unlocking new abilities
 type ResearchManager struct {
 	technologies     map[string]Technology
 	researchedTechs  map[string]bool
 	currentResearch  string
 	researchProgress float64
+	queue            []string // Technologies to research next, in order
+	ages             []string // All ages in order, used to gate technologies
+	foodSources      []string // Resources affected by "food" effects
 }
 
 // Technology represents a researchable technology
 type Technology struct {
-	Name          string
-	Description   string
-	Age           string
-	Cost          float64
-	Prerequisites []string
-	Unlocks       map[string]interface{}
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Age           string       `json:"age"`
+	Cost          float64      `json:"cost"`
+	Prerequisites []string     `json:"prerequisites"`
+	Effects       []TechEffect `json:"effects"`
+}
+
+// ResearchState is the persistent part of the research manager, as stored in save files
+type ResearchState struct {
+	Researched []string `json:"researched"`
+	Current    string   `json:"current,omitempty"`
+	Progress   float64  `json:"progress,omitempty"`
+	Queue      []string `json:"queue,omitempty"`
 }
 
-// NewResearchManager creates a new research manager
-func NewResearchManager() *ResearchManager {
+// NewResearchManager creates a new research manager from the content pack
+func NewResearchManager(content *ContentPack) *ResearchManager {
 	rm := &ResearchManager{
 		technologies:    make(map[string]Technology),
 		researchedTechs: make(map[string]bool),
 		currentResearch: "",
+		ages:            content.AgeNames(),
+		foodSources:     content.FoodSources(),
 	}
-
-	// Define technologies
-	rm.technologies["agriculture"] = Technology{
-		Name:          "Agriculture",
-		Description:   "Improve food production methods",
-		Age:           "Stone Age",
-		Cost:          20,
-		Prerequisites: []string{},
-		Unlocks: map[string]interface{}{
-			"food_production_bonus": 0.2,
-		},
-	}
-
-	rm.technologies["Bows"] = Technology{
-		Name:          "Bows",
-		Description:   "Develop bows for hunting and defense",
-		Age:           "Stone Age",
-		Cost:          30,
-		Prerequisites: []string{"agriculture"},
-		Unlocks: map[string]interface{}{
-			"hunting_production_bonus": 0.15,
-		},
-	}
-
-	rm.technologies["toolmaking"] = Technology{
-		Name:          "Toolmaking",
-		Description:   "Develop better tools for resource gathering",
-		Age:           "Stone Age",
-		Cost:          25,
-		Prerequisites: []string{},
-		Unlocks: map[string]interface{}{
-			"resource_production_bonus": 0.1,
-		},
-	}
-
-	rm.technologies["writing"] = Technology{
-		Name:          "Writing",
-		Description:   "Develop a writing system to record knowledge",
-		Age:           "Bronze Age",
-		Cost:          40,
-		Prerequisites: []string{},
-		Unlocks: map[string]interface{}{
-			"knowledge_production_bonus": 0.2,
-		},
-	}
-
-	rm.technologies["metallurgy"] = Technology{
-		Name:          "Metallurgy",
-		Description:   "Learn how to work with metals",
-		Age:           "Bronze Age",
-		Cost:          50,
-		Prerequisites: []string{},
-		Unlocks: map[string]interface{}{
-			"new_building": "foundry",
-		},
-	}
-
-	rm.technologies["mathematics"] = Technology{
-		Name:          "Mathematics",
-		Description:   "Develop mathematical concepts",
-		Age:           "Iron Age",
-		Cost:          60,
-		Prerequisites: []string{"writing"},
-		Unlocks: map[string]interface{}{
-			"knowledge_production_bonus": 0.3,
-			"resource_production_bonus":  0.1,
-		},
+	for name, tech := range content.Technologies {
+		rm.technologies[name] = tech
 	}
-
 	return rm
 }
 
@@ -123,10 +76,12 @@ func (rm *ResearchManager) StartResearch(techName string, knowledgePoints float6
 	return true
 }
 
-// ContinueResearch adds knowledge points to current research
-func (rm *ResearchManager) ContinueResearch(knowledgePoints float64) (string, bool) {
+// ContinueResearch adds knowledge points to current research. When the
+// research completes it also returns the knowledge points left over beyond
+// the technology's cost.
+func (rm *ResearchManager) ContinueResearch(knowledgePoints float64) (string, bool, float64) {
 	if rm.currentResearch == "" {
-		return "", false
+		return "", false, 0
 	}
 
 	tech := rm.technologies[rm.currentResearch]
@@ -135,13 +90,177 @@ func (rm *ResearchManager) ContinueResearch(knowledgePoints float64) (string, bo
 	// Check if research is complete
 	if rm.researchProgress >= tech.Cost {
 		completedTech := rm.currentResearch
+		surplus := rm.researchProgress - tech.Cost
 		rm.researchedTechs[completedTech] = true
 		rm.currentResearch = ""
 		rm.researchProgress = 0
-		return completedTech, true
+		return completedTech, true, surplus
+	}
+
+	return "", false, 0
+}
+
+// Enqueue adds a technology to the end of the research queue, first adding
+// any prerequisites that are neither researched, being researched nor
+// queued, so that every technology comes after the ones it needs. It returns
+// the technologies added, in order.
+func (rm *ResearchManager) Enqueue(techName, currentAge string) ([]string, error) {
+	techName, exists := rm.Resolve(techName)
+	if !exists {
+		return nil, fmt.Errorf("Unknown technology '%s'", techName)
+	}
+	if rm.researchedTechs[techName] {
+		return nil, fmt.Errorf("%s has already been researched", techName)
+	}
+	if rm.currentResearch == techName {
+		return nil, fmt.Errorf("%s is already being researched", techName)
+	}
+	if rm.isQueued(techName) {
+		return nil, fmt.Errorf("%s is already in the research queue", techName)
+	}
+
+	added := []string{}
+	visiting := make(map[string]bool)
+	done := make(map[string]bool)
+	var visit func(name string) error
+	visit = func(name string) error {
+		tech, exists := rm.technologies[name]
+		if !exists {
+			return fmt.Errorf("%s needs the unknown technology '%s'", techName, name)
+		}
+		if rm.researchedTechs[name] || rm.currentResearch == name || rm.isQueued(name) || done[name] {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("the prerequisites of %s form a cycle", name)
+		}
+		if rm.ageIndex(tech.Age) > rm.ageIndex(currentAge) {
+			return fmt.Errorf("%s can't be researched before the %s", name, tech.Age)
+		}
+
+		visiting[name] = true
+		prereqs := append([]string(nil), tech.Prerequisites...)
+		sort.Strings(prereqs)
+		for _, prereq := range prereqs {
+			if err := visit(prereq); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+		done[name] = true
+		added = append(added, name)
+		return nil
+	}
+	if err := visit(techName); err != nil {
+		return nil, err
+	}
+
+	rm.queue = append(rm.queue, added...)
+	return added, nil
+}
+
+// Dequeue removes a technology from the research queue, along with queued
+// technologies that need it. It returns the technologies removed.
+func (rm *ResearchManager) Dequeue(techName string) []string {
+	techName, _ = rm.Resolve(techName)
+	if !rm.isQueued(techName) {
+		return nil
+	}
+
+	removed := map[string]bool{techName: true}
+	kept := []string{}
+	for _, name := range rm.queue {
+		// Prerequisites come first, so anything needing a removed technology
+		// is only seen after it
+		for _, prereq := range rm.technologies[name].Prerequisites {
+			if removed[prereq] {
+				removed[name] = true
+			}
+		}
+		if !removed[name] {
+			kept = append(kept, name)
+		}
+	}
+
+	dropped := []string{}
+	for _, name := range rm.queue {
+		if removed[name] {
+			dropped = append(dropped, name)
+		}
 	}
+	rm.queue = kept
+	return dropped
+}
 
-	return "", false
+// GetQueue returns the queued technologies, next first
+func (rm *ResearchManager) GetQueue() []string {
+	return append([]string(nil), rm.queue...)
+}
+
+// NextQueued returns the technology at the front of the queue, if any
+func (rm *ResearchManager) NextQueued() (string, bool) {
+	if len(rm.queue) == 0 {
+		return "", false
+	}
+	return rm.queue[0], true
+}
+
+// PopQueued removes the technology at the front of the queue
+func (rm *ResearchManager) PopQueued() {
+	if len(rm.queue) > 0 {
+		rm.queue = rm.queue[1:]
+	}
+}
+
+// Resolve finds a technology by name, ignoring case
+func (rm *ResearchManager) Resolve(name string) (string, bool) {
+	if _, exists := rm.technologies[name]; exists {
+		return name, true
+	}
+	for techName := range rm.technologies {
+		if strings.EqualFold(techName, name) {
+			return techName, true
+		}
+	}
+	return name, false
+}
+
+// unavailableReason explains why a technology that hasn't been researched
+// can't be started in the given age, or returns "" if it can
+func (rm *ResearchManager) unavailableReason(techName, currentAge string) string {
+	tech, exists := rm.technologies[techName]
+	if !exists {
+		return "unknown technology"
+	}
+	if rm.ageIndex(tech.Age) > rm.ageIndex(currentAge) {
+		return "needs the " + tech.Age
+	}
+	for _, prereq := range tech.Prerequisites {
+		if !rm.researchedTechs[prereq] {
+			return "needs " + prereq + ", which hasn't been researched"
+		}
+	}
+	return ""
+}
+
+// isQueued reports whether a technology is in the research queue
+func (rm *ResearchManager) isQueued(techName string) bool {
+	for _, name := range rm.queue {
+		if name == techName {
+			return true
+		}
+	}
+	return false
+}
+
+// ageIndex returns the position of an age; unknown ages count as the first
+func (rm *ResearchManager) ageIndex(age string) int {
+	for i, name := range rm.ages {
+		if name == age {
+			return i
+		}
+	}
+	return 0
 }
 
 // GetProgress returns the current research progress
@@ -165,7 +284,7 @@ func (rm *ResearchManager) GetAvailableTechnologies(currentAge string) map[strin
 
 	// Get age index
 	ageIndex := 0
-	ages := []string{"Stone Age", "Bronze Age", "Iron Age", "Medieval Age", "Renaissance Age", "Industrial Age", "Modern Age"}
+	ages := rm.ages
 	for i, age := range ages {
 		if age == currentAge {
 			ageIndex = i
@@ -219,54 +338,97 @@ func (rm *ResearchManager) GetAllTechnologies() map[string]Technology {
 	return rm.technologies
 }
 
-// ApplyResearchBonuses applies research bonuses to resource production
-func (rm *ResearchManager) ApplyResearchBonuses(resourceRates map[string]float64) map[string]float64 {
-	result := make(map[string]float64)
-	for resource, rate := range resourceRates {
-		result[resource] = rate
+// GetState returns the research state for saving
+func (rm *ResearchManager) GetState() ResearchState {
+	state := ResearchState{
+		Researched: []string{},
+		Current:    rm.currentResearch,
+		Progress:   rm.researchProgress,
+		Queue:      rm.GetQueue(),
 	}
-
-	// Apply global resource production bonus
-	globalBonus := 0.0
-	for tech, researched := range rm.researchedTechs {
+	for name, researched := range rm.researchedTechs {
 		if researched {
-			if bonus, exists := rm.technologies[tech].Unlocks["resource_production_bonus"]; exists {
-				globalBonus += bonus.(float64)
-			}
+			state.Researched = append(state.Researched, name)
 		}
 	}
+	sort.Strings(state.Researched)
+	return state
+}
 
-	if globalBonus > 0 {
-		for resource, rate := range result {
-			result[resource] = rate * (1 + globalBonus)
+// Restore replaces the research state with a saved one. Technologies that
+// are not part of the current content are dropped and returned.
+func (rm *ResearchManager) Restore(state ResearchState) []string {
+	var unknown []string
+
+	rm.researchedTechs = make(map[string]bool)
+	for _, name := range state.Researched {
+		if _, exists := rm.technologies[name]; exists {
+			rm.researchedTechs[name] = true
+		} else {
+			unknown = append(unknown, name)
 		}
 	}
 
-	// Apply specific resource bonuses
-	for tech, researched := range rm.researchedTechs {
-		if researched {
-			for unlock, value := range rm.technologies[tech].Unlocks {
-				if resource := getResourceBonusType(unlock); resource != "" {
-					if bonus, ok := value.(float64); ok {
-						if _, exists := result[resource]; exists {
-							result[resource] = result[resource] * (1 + bonus)
-						}
-					}
+	rm.currentResearch = ""
+	rm.researchProgress = 0
+	if state.Current != "" {
+		if _, exists := rm.technologies[state.Current]; exists && !rm.researchedTechs[state.Current] {
+			rm.currentResearch = state.Current
+			rm.researchProgress = state.Progress
+		} else if !exists {
+			unknown = append(unknown, state.Current)
+		}
+	}
+
+	rm.queue = nil
+	for _, name := range state.Queue {
+		if _, exists := rm.technologies[name]; !exists {
+			unknown = append(unknown, name)
+		} else if !rm.researchedTechs[name] && name != rm.currentResearch && !rm.isQueued(name) {
+			rm.queue = append(rm.queue, name)
+		}
+	}
+
+	return unknown
+}
+
+// GetModifiers combines the effects of all researched technologies
+func (rm *ResearchManager) GetModifiers() *ResearchModifiers {
+	mods := NewResearchModifiers()
+
+	for name, researched := range rm.researchedTechs {
+		if !researched {
+			continue
+		}
+
+		for _, effect := range rm.technologies[name].Effects {
+			switch effect.Type {
+			case EffectProductionMultiplier:
+				for _, resource := range rm.expandResource(effect.Resource) {
+					mods.ProductionBonuses[resource] += effect.Value
 				}
+			case EffectFlatBonus:
+				mods.FlatBonuses[effect.Resource] += effect.Value
+			case EffectUnlockBuilding:
+				mods.UnlockedBuildings = append(mods.UnlockedBuildings, effect.Building)
+			case EffectUnlockVillager:
+				mods.UnlockedVillagers = append(mods.UnlockedVillagers, effect.Villager)
+			case EffectCapacity:
+				mods.CapacityBonus += int(effect.Value)
+			case EffectCostReduction:
+				mods.CostReductions[effect.Building] += effect.Value
 			}
 		}
 	}
 
-	return result
+	return mods
 }
 
-// Helper function to extract resource type from bonus name
-func getResourceBonusType(bonusName string) string {
-	resourceTypes := []string{"foraging", "hunting", "wood", "stone", "gold", "knowledge"}
-	for _, resource := range resourceTypes {
-		if bonusName == resource+"_production_bonus" {
-			return resource
-		}
+// expandResource maps the generic "food" resource onto every food source,
+// keeping "food" itself for production that is credited as generic food
+func (rm *ResearchManager) expandResource(resource string) []string {
+	if resource != "food" {
+		return []string{resource}
 	}
-	return ""
+	return append([]string{"food"}, rm.foodSources...)
 }
@@ -4,39 +4,36 @@ package game
 type ResourceManager struct {
 	resources       map[string]float64
 	collectionRates map[string]float64
-	foodSources     []string // List of resources that count as food sources
+	foodBonuses     map[string]float64 // Extra food per gatherer, as a fraction of the base rate
+	foodSources     []string           // List of resources that count as food sources
+	defaultFood     string             // Resource that receives generic "food"
 }
 
-// NewResourceManager creates a new resource manager
-func NewResourceManager() *ResourceManager {
+// NewResourceManager creates a new resource manager from the content pack
+func NewResourceManager(content *ContentPack) *ResourceManager {
 	rm := &ResourceManager{
-		resources: map[string]float64{
-			"foraging":  0,
-			"wood":      0,
-			"stone":     0,
-			"gold":      0,
-			"knowledge": 0,
-			"hunting":   0,
-		},
-		collectionRates: map[string]float64{
-			"foraging":  1.0,
-			"wood":      1.0,
-			"stone":     0.5,
-			"gold":      0.2,
-			"knowledge": 0.1,
-			"hunting":   1.8,
-		},
-		foodSources: []string{"foraging", "hunting"}, // Define which resources count as food
+		resources:       make(map[string]float64),
+		collectionRates: make(map[string]float64),
+		foodBonuses:     make(map[string]float64),
+		foodSources:     content.FoodSources(), // Define which resources count as food
+		defaultFood:     content.DefaultFood,
+	}
+	for name, def := range content.Resources {
+		rm.resources[name] = 0
+		rm.collectionRates[name] = def.CollectionRate
+		if def.FoodBonus > 0 {
+			rm.foodBonuses[name] = def.FoodBonus
+		}
 	}
 	return rm
 }
 
 // Add adds resources to the inventory
 func (rm *ResourceManager) Add(resource string, amount float64) bool {
-	// Special case for "food" - add to "foraging" instead
+	// Special case for "food" - add to the default food resource instead
 	if resource == "food" {
-		if _, exists := rm.resources["foraging"]; exists {
-			rm.resources["foraging"] += amount
+		if _, exists := rm.resources[rm.defaultFood]; exists {
+			rm.resources[rm.defaultFood] += amount
 			return true
 		}
 		return false
@@ -103,6 +100,11 @@ func (rm *ResourceManager) GetCollectionRate(resource string) float64 {
 	return rm.collectionRates[resource]
 }
 
+// GetFoodBonus returns the bonus food fraction gathered alongside a resource
+func (rm *ResourceManager) GetFoodBonus(resource string) float64 {
+	return rm.foodBonuses[resource]
+}
+
 // SetCollectionRate sets the collection rate for a resource
 func (rm *ResourceManager) SetCollectionRate(resource string, rate float64) bool {
 	if _, exists := rm.collectionRates[resource]; exists {
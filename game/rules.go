@@ -0,0 +1,360 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule is an automation rule, e.g. "when wood >= 20 build hut". Enabled
+// rules are checked every tick; whenever the condition holds and the action
+// can be carried out, it is.
+type Rule struct {
+	ID      int    `json:"id"`
+	Text    string `json:"text"` // The rule as entered; parsed again when a save is loaded
+	Enabled bool   `json:"enabled"`
+	Fired   int    `json:"fired,omitempty"` // Times the action has been carried out
+
+	condition *Condition
+	action    ruleAction
+}
+
+// ruleActionKind identifies what a rule does when it fires
+type ruleActionKind int
+
+const (
+	actionAssign ruleActionKind = iota
+	actionBuild
+	actionRecruit
+	actionResearch
+)
+
+// ruleAction is the action part of a rule
+type ruleAction struct {
+	kind         ruleActionKind
+	villagerType string // For actionAssign and actionRecruit
+	target       string // Task, building or technology; "" researches the cheapest available
+	count        int    // How many; 0 assigns every idle villager
+}
+
+// ruleActionVerbs are the words an action can start with
+var ruleActionVerbs = map[string]bool{"assign": true, "build": true, "recruit": true, "research": true, "start": true}
+
+// RuleManager holds the player's automation rules
+type RuleManager struct {
+	rules  []*Rule
+	nextID int
+}
+
+// NewRuleManager creates an empty rule manager
+func NewRuleManager() *RuleManager {
+	return &RuleManager{nextID: 1}
+}
+
+// Add adds a rule, giving it the next free ID
+func (rm *RuleManager) Add(rule *Rule) {
+	rule.ID = rm.nextID
+	rm.nextID++
+	rm.rules = append(rm.rules, rule)
+}
+
+// Remove removes the rule with the given ID, reporting whether it existed
+func (rm *RuleManager) Remove(id int) bool {
+	for i, rule := range rm.rules {
+		if rule.ID == id {
+			rm.rules = append(rm.rules[:i], rm.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the rule with the given ID
+func (rm *RuleManager) Get(id int) (*Rule, bool) {
+	for _, rule := range rm.rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return nil, false
+}
+
+// GetAll returns the rules in the order they were added
+func (rm *RuleManager) GetAll() []*Rule {
+	return rm.rules
+}
+
+// GetState returns copies of the rules for saving
+func (rm *RuleManager) GetState() []Rule {
+	state := make([]Rule, 0, len(rm.rules))
+	for _, rule := range rm.rules {
+		state = append(state, *rule)
+	}
+	return state
+}
+
+// restoreRules replaces the rules with those from a save. Rules that no
+// longer make sense, e.g. because the content pack changed, are dropped with
+// a warning. The caller must hold the engine lock.
+func (ge *GameEngine) restoreRules(saved []Rule) {
+	ge.Rules = NewRuleManager()
+	for _, s := range saved {
+		rule, err := ge.parseRule(s.Text)
+		if err != nil {
+			ge.Display.ShowMessage(fmt.Sprintf("Ignoring invalid rule #%d in save: %v", s.ID, err), "warning")
+			continue
+		}
+		rule.ID = s.ID
+		rule.Enabled = s.Enabled
+		rule.Fired = s.Fired
+		ge.Rules.rules = append(ge.Rules.rules, rule)
+		if s.ID >= ge.Rules.nextID {
+			ge.Rules.nextID = s.ID + 1
+		}
+	}
+}
+
+// parseRule parses "when <condition> [then] <action>". The caller must hold
+// the engine lock.
+func (ge *GameEngine) parseRule(text string) (*Rule, error) {
+	words := strings.Fields(text)
+	if len(words) > 0 && strings.EqualFold(words[0], "when") {
+		words = words[1:]
+	}
+	rule := &Rule{Text: "when " + strings.Join(words, " "), Enabled: true}
+
+	// An explicit "then" separates the condition from the action
+	for i, word := range words {
+		if strings.EqualFold(word, "then") {
+			return ge.completeRule(rule, words[:i], words[i+1:])
+		}
+	}
+
+	// Otherwise the action starts at the first action verb that leaves a
+	// valid condition before it; "research idle" shows the verb alone
+	// doesn't settle it
+	err := fmt.Errorf("expected 'when <condition> <action>'; actions are assign, build, recruit and research")
+	for i := 1; i < len(words); i++ {
+		if !ruleActionVerbs[strings.ToLower(words[i])] {
+			continue
+		}
+		var parsed *Rule
+		if parsed, err = ge.completeRule(rule, words[:i], words[i:]); err == nil {
+			return parsed, nil
+		}
+	}
+	return nil, err
+}
+
+// completeRule fills in a rule's condition and action
+func (ge *GameEngine) completeRule(rule *Rule, condition, action []string) (*Rule, error) {
+	if len(action) == 0 {
+		return nil, fmt.Errorf("missing action after the condition")
+	}
+	var err error
+	if rule.condition, err = ge.parseCondition(strings.Join(condition, " ")); err != nil {
+		return nil, err
+	}
+	if rule.action, err = ge.parseRuleAction(action); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// parseRuleAction parses one of:
+//
+//	assign [count|all] [villager type] to <task>
+//	build <building> [count]
+//	recruit <villager type> [count]
+//	research <technology>
+//	research cheapest / start cheapest available tech
+func (ge *GameEngine) parseRuleAction(words []string) (ruleAction, error) {
+	words = strings.Fields(strings.ToLower(strings.Join(words, " ")))
+	verb, rest := words[0], words[1:]
+
+	switch verb {
+	case "assign":
+		return ge.parseAssignAction(rest)
+	case "build":
+		if len(rest) == 0 || len(rest) > 2 {
+			return ruleAction{}, fmt.Errorf("expected 'build <building> [count]'")
+		}
+		if _, exists := ge.Buildings.GetAll()[rest[0]]; !exists {
+			return ruleAction{}, fmt.Errorf("unknown building %q", rest[0])
+		}
+		if base := ge.Buildings.GetUpgradeBase(rest[0]); base != "" {
+			return ruleAction{}, fmt.Errorf("%s is built by upgrading a %s", rest[0], base)
+		}
+		count, err := actionCount(rest[1:])
+		return ruleAction{kind: actionBuild, target: rest[0], count: count}, err
+	case "recruit":
+		if len(rest) == 0 || len(rest) > 2 {
+			return ruleAction{}, fmt.Errorf("expected 'recruit <villager type> [count]'")
+		}
+		if _, exists := ge.Villagers.GetAll()[rest[0]]; !exists {
+			return ruleAction{}, fmt.Errorf("unknown villager type %q", rest[0])
+		}
+		count, err := actionCount(rest[1:])
+		return ruleAction{kind: actionRecruit, villagerType: rest[0], count: count}, err
+	case "research", "start":
+		if len(rest) > 0 && rest[0] == "cheapest" {
+			return ruleAction{kind: actionResearch}, nil
+		}
+		if verb == "research" && len(rest) == 1 {
+			tech, exists := ge.Research.Resolve(rest[0])
+			if !exists {
+				return ruleAction{}, fmt.Errorf("unknown technology %q", rest[0])
+			}
+			return ruleAction{kind: actionResearch, target: tech}, nil
+		}
+		return ruleAction{}, fmt.Errorf("expected 'research <technology>' or 'research cheapest'")
+	}
+	return ruleAction{}, fmt.Errorf("unknown action %q; actions are assign, build, recruit and research", verb)
+}
+
+// parseAssignAction parses the words after "assign"
+func (ge *GameEngine) parseAssignAction(words []string) (ruleAction, error) {
+	usage := fmt.Errorf("expected 'assign [count|all] [villager type] to <task>'")
+	if len(words) < 2 || words[len(words)-2] != "to" {
+		return ruleAction{}, usage
+	}
+	action := ruleAction{kind: actionAssign, villagerType: "villager", target: words[len(words)-1]}
+
+	before := words[:len(words)-2]
+	if len(before) > 0 && before[0] != "all" {
+		if count, err := strconv.Atoi(before[0]); err == nil {
+			if count <= 0 {
+				return ruleAction{}, fmt.Errorf("count must be a positive number")
+			}
+			action.count = count
+			before = before[1:]
+		}
+	} else if len(before) > 0 {
+		before = before[1:]
+	}
+	switch len(before) {
+	case 0:
+	case 1:
+		action.villagerType = strings.TrimSuffix(before[0], "s")
+		if _, exists := ge.Villagers.GetAll()[before[0]]; exists {
+			action.villagerType = before[0]
+		}
+	default:
+		return ruleAction{}, usage
+	}
+
+	if _, exists := ge.Villagers.GetAll()[action.villagerType]; !exists {
+		return ruleAction{}, fmt.Errorf("unknown villager type %q", action.villagerType)
+	}
+	if !containsFold(ge.Villagers.GetTasks(action.villagerType), action.target) {
+		return ruleAction{}, fmt.Errorf("%s villagers can't work on %q", action.villagerType, action.target)
+	}
+	return action, nil
+}
+
+// actionCount parses an optional count, defaulting to 1
+func actionCount(words []string) (int, error) {
+	if len(words) == 0 {
+		return 1, nil
+	}
+	count, err := strconv.Atoi(words[0])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("count must be a positive number")
+	}
+	return count, nil
+}
+
+// applyRules carries out the actions of the enabled rules whose conditions
+// hold, in the order the rules were added. The caller must hold the engine
+// lock.
+func (ge *GameEngine) applyRules() {
+	for _, rule := range ge.Rules.GetAll() {
+		if !rule.Enabled || !rule.condition.evaluate(ge) {
+			continue
+		}
+		if done := ge.applyRuleAction(rule.action); done != "" {
+			rule.Fired++
+			ge.Display.ShowMessage(fmt.Sprintf("Rule #%d: %s", rule.ID, done), "info")
+		}
+	}
+}
+
+// applyRuleAction carries out an action as far as possible, describing what
+// was done, or returning "" if nothing could be
+func (ge *GameEngine) applyRuleAction(action ruleAction) string {
+	switch action.kind {
+	case actionAssign:
+		idle := ge.Villagers.GetAll()[action.villagerType].Assignment["idle"]
+		count := idle
+		if action.count > 0 && action.count < idle {
+			count = action.count
+		}
+		if count == 0 || !ge.Villagers.Assign(action.villagerType, action.target, count) {
+			return ""
+		}
+		return fmt.Sprintf("assigned %d %ss to %s", count, action.villagerType, action.target)
+
+	case actionBuild:
+		built, started := 0, 0
+		for built+started < action.count {
+			site, err := ge.Commands.build(action.target)
+			if err != nil {
+				break
+			}
+			if site != nil {
+				started++
+			} else {
+				built++
+			}
+		}
+		switch {
+		case built+started == 0:
+			return ""
+		case started == 0:
+			return fmt.Sprintf("built %d %s", built, action.target)
+		}
+		return fmt.Sprintf("started building %d %s", built+started, action.target)
+
+	case actionRecruit:
+		if ge.Commands.recruit(action.villagerType, action.count) != nil {
+			return ""
+		}
+		return fmt.Sprintf("recruited %d %ss", action.count, action.villagerType)
+
+	case actionResearch:
+		if current, _, _ := ge.Research.GetProgress(); current != "" {
+			return ""
+		}
+		tech := action.target
+		if tech == "" {
+			tech = ge.cheapestTechnology()
+		}
+		if tech == "" || ge.Commands.startResearch(tech) != nil {
+			return ""
+		}
+		return "started researching " + tech
+	}
+	return ""
+}
+
+// cheapestTechnology returns the available technology with the lowest cost,
+// or "" if there is none
+func (ge *GameEngine) cheapestTechnology() string {
+	available := ge.Research.GetAvailableTechnologies(ge.Age)
+	names := make([]string, 0, len(available))
+	for name := range available {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := available[names[i]], available[names[j]]
+		if a.Cost != b.Cost {
+			return a.Cost < b.Cost
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 {
+		return ""
+	}
+	return names[0]
+}
@@ -5,39 +5,75 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // GameSave represents a saved game state
 type GameSave struct {
+	Header         *SaveHeader             `json:"header,omitempty"` // Must stay first so it can be read on its own
+	SchemaVersion  int                     `json:"schemaVersion"`
 	Timestamp      time.Time               `json:"timestamp"`
+	CivName        string                  `json:"civName,omitempty"`
 	Tick           int                     `json:"tick"`
 	Age            string                  `json:"age"`
 	Resources      map[string]float64      `json:"resources"`
 	Buildings      map[string]int          `json:"buildings"`
+	Construction   []ConstructionSite      `json:"construction,omitempty"`
 	Villagers      map[string]VillagerInfo `json:"villagers"`
+	Research       *ResearchState          `json:"research,omitempty"`
+	Rules          []Rule                  `json:"rules,omitempty"`
+	BuildQueue     []BuildOrder            `json:"buildQueue,omitempty"`
 	Stats          *GameStats              `json:"stats"`
 	LastUpdateTime time.Time               `json:"lastUpdateTime"`
+	Speed          float64                 `json:"speed,omitempty"`
+	Paused         bool                    `json:"paused,omitempty"`
+	Checksum       string                  `json:"checksum,omitempty"`  // SHA-256 of the rest of the save
+	Signature      string                  `json:"signature,omitempty"` // HMAC with the local save key, if one is configured
 }
 
 // SaveGame saves the current game state to a file
 func (ge *GameEngine) SaveGame(filename string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.saveGame(filename)
+}
+
+// saveGame writes the save file; the caller must hold the engine lock
+func (ge *GameEngine) saveGame(filename string) error {
 	// Create save directory if it doesn't exist
-	saveDir := filepath.Join(".", "data", "saves")
+	saveDir := SaveDir()
 	if err := os.MkdirAll(saveDir, 0755); err != nil {
 		return fmt.Errorf("failed to create save directory: %w", err)
 	}
 
 	// Prepare save data
+	research := ge.Research.GetState()
 	save := GameSave{
+		SchemaVersion:  CurrentSaveVersion,
 		Timestamp:      time.Now(),
+		CivName:        ge.CivName,
 		Tick:           ge.Tick,
 		Age:            ge.Age,
 		Resources:      ge.Resources.GetAll(),
 		Buildings:      ge.Buildings.GetAll(),
+		Construction:   ge.Buildings.GetConstruction(),
 		Villagers:      ge.Villagers.GetAll(),
+		Research:       &research,
+		Rules:          ge.Rules.GetState(),
+		BuildQueue:     ge.Queue.GetAll(),
 		Stats:          ge.Stats,
 		LastUpdateTime: ge.LastUpdateTime,
+		Speed:          ge.Speed,
+		Paused:         ge.Paused,
+	}
+
+	save.Header = NewSaveHeader(&save)
+
+	// Add the checksum so modified or corrupted saves can be detected
+	if err := sealSave(&save); err != nil {
+		return fmt.Errorf("failed to checksum save data: %w", err)
 	}
 
 	// Marshal to JSON
@@ -46,18 +82,37 @@ func (ge *GameEngine) SaveGame(filename string) error {
 		return fmt.Errorf("failed to marshal save data: %w", err)
 	}
 
-	// Write to file
+	// Write to a temporary file and rename it into place, so a crash
+	// mid-write can't corrupt an existing save
 	savePath := filepath.Join(saveDir, filename+".json")
-	if err := os.WriteFile(savePath, saveData, 0644); err != nil {
+	if err := writeFileAtomic(savePath, saveData); err != nil {
 		return fmt.Errorf("failed to write save file: %w", err)
 	}
 
 	return nil
 }
 
-// LoadGame loads a game state from a file
+// LoadGame loads a game state from a file. Saves that fail their integrity
+// check are refused with ErrSaveTampered.
 func (ge *GameEngine) LoadGame(filename string) error {
-	savePath := filepath.Join(".", "data", "saves", filename+".json")
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.loadGame(filename, false)
+}
+
+// LoadGameUnverified loads a game state even if the save fails its integrity
+// check. Such loads are recorded in the game statistics.
+func (ge *GameEngine) LoadGameUnverified(filename string) error {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
+	return ge.loadGame(filename, true)
+}
+
+// loadGame restores state from a save file; the caller must hold the engine lock
+func (ge *GameEngine) loadGame(filename string, allowUnverified bool) error {
+	savePath := filepath.Join(SaveDir(), filename+".json")
 
 	// Read the save file
 	saveData, err := os.ReadFile(savePath)
@@ -65,10 +120,19 @@ func (ge *GameEngine) LoadGame(filename string) error {
 		return fmt.Errorf("failed to read save file: %w", err)
 	}
 
-	// Unmarshal the JSON
-	var save GameSave
-	if err := json.Unmarshal(saveData, &save); err != nil {
-		return fmt.Errorf("failed to unmarshal save data - save file may be corrupted or from an incompatible version: %w", err)
+	// Check the save hasn't been modified since it was written
+	integrity, err := VerifySave(saveData)
+	if err != nil {
+		return err
+	}
+	if integrity == IntegrityTampered && !allowUnverified {
+		return ErrSaveTampered
+	}
+
+	// Decode the JSON, upgrading saves from older versions
+	save, err := DecodeSave(saveData)
+	if err != nil {
+		return err
 	}
 
 	// Validate essential fields
@@ -80,15 +144,16 @@ func (ge *GameEngine) LoadGame(filename string) error {
 	}
 
 	// Note: We don't stop the game engine anymore since it can cause the main loop to exit
-	// The game state update is atomic enough that we can safely update while running
+	// The engine lock keeps ticks and rendering from observing a half-restored state
 
 	// Restore game state safely
 	ge.Tick = save.Tick
 	ge.Age = save.Age
+	ge.CivName = save.CivName
 
 	// Restore resources (ensure Resources manager exists)
 	if ge.Resources == nil {
-		ge.Resources = NewResourceManager()
+		ge.Resources = NewResourceManager(ge.Content)
 	}
 	// Clear and restore resources
 	ge.Resources.resources = make(map[string]float64)
@@ -98,7 +163,7 @@ func (ge *GameEngine) LoadGame(filename string) error {
 
 	// Restore buildings (ensure Buildings manager exists)
 	if ge.Buildings == nil {
-		ge.Buildings = NewBuildingManager()
+		ge.Buildings = NewBuildingManager(ge.Content)
 	}
 	// Clear and restore buildings
 	ge.Buildings.buildings = make(map[string]int)
@@ -107,42 +172,49 @@ func (ge *GameEngine) LoadGame(filename string) error {
 			ge.Buildings.buildings[building] = count
 		}
 	}
+	if unknown := ge.Buildings.RestoreConstruction(save.Construction); len(unknown) > 0 {
+		ge.Display.ShowMessage("Ignoring construction of unknown buildings in save: "+strings.Join(unknown, ", "), "warning")
+	}
+
+	// Restore villagers on top of fresh definitions, so food costs and
+	// villager types absent from the save come from the content pack
+	ge.Villagers = NewVillagerManager(ge.Content)
+	for vtype, info := range save.Villagers {
+		v, exists := ge.Villagers.villagers[vtype]
+		if !exists {
+			ge.Display.ShowMessage("Ignoring unknown villager type in save: "+vtype, "warning")
+			continue
+		}
 
-	// Restore villagers (ensure Villagers manager exists)
-	if ge.Villagers == nil {
-		ge.Villagers = NewVillagerManager()
-	}
-	// Clear and restore villagers
-	ge.Villagers.villagers = make(map[string]*VillagerType)
-	if save.Villagers != nil {
-		for vtype, info := range save.Villagers {
-			// Create new villager entry with safe defaults
-			assignment := make(VillagerAssignment)
-			if info.Assignment != nil {
-				for resource, count := range info.Assignment {
-					assignment[resource] = count
-				}
-			}
-
-			ge.Villagers.villagers[vtype] = &VillagerType{
-				Count:      info.Count,
-				FoodCost:   0.5, // Default food cost
-				Assignment: assignment,
-			}
+		v.Count = info.Count
+		for resource, count := range info.Assignment {
+			v.Assignment[resource] = count
 		}
 	}
 
 	// Ensure other managers exist
 	if ge.Progress == nil {
-		ge.Progress = NewProgressManager()
-	}
-	if ge.Research == nil {
-		ge.Research = NewResearchManager()
+		ge.Progress = NewProgressManager(ge.Content)
 	}
 	// if ge.Library == nil {
 	// 	ge.Library = NewLibrarySystem()
 	// }
 
+	// Restore research; saves from before research was persisted start with none
+	ge.Research = NewResearchManager(ge.Content)
+	if save.Research != nil {
+		if unknown := ge.Research.Restore(*save.Research); len(unknown) > 0 {
+			ge.Display.ShowMessage("Ignoring unknown technologies in save: "+strings.Join(unknown, ", "), "warning")
+		}
+	}
+
+	// Technology effects apply to the restored managers
+	ge.applyResearch()
+
+	// Rules are parsed again, against the restored state
+	ge.restoreRules(save.Rules)
+	ge.restoreBuildQueue(save.BuildQueue)
+
 	// Restore or create statistics
 	if save.Stats != nil {
 		ge.Stats = save.Stats
@@ -151,12 +223,29 @@ func (ge *GameEngine) LoadGame(filename string) error {
 			ge.Stats = NewGameStats()
 		}
 	}
+	if integrity == IntegrityTampered {
+		ge.Stats.AddUnverifiedLoad(ge.Tick, filename)
+		ge.Display.ShowMessage("Loaded '"+filename+"' without verification; the save has been modified", "warning")
+	} else if integrity == IntegrityUnsigned {
+		ge.Display.ShowMessage("'"+filename+"' isn't signed with your save key yet; it will be signed the next time it is saved", "info")
+	}
 
-	// Restore LastUpdateTime or set to current time
-	if !save.LastUpdateTime.IsZero() {
-		ge.LastUpdateTime = save.LastUpdateTime
-	} else {
-		ge.LastUpdateTime = time.Now()
+	// Restore the game clock (saves from before speed control run at 1x)
+	if save.Speed < MinSpeed || save.Speed > MaxSpeed {
+		save.Speed = 1
+	}
+	ge.setSpeed(save.Speed)
+	ge.Paused = save.Paused
+
+	// Simulate the time that passed since the save was last updated, unless
+	// the game was saved while paused. Only a running game clock catches up:
+	// headless runs simulate their ticks explicitly and must not depend on
+	// when the save was written.
+	ge.LastUpdateTime = time.Now()
+	if ge.live && !save.LastUpdateTime.IsZero() && !save.Paused {
+		if report := ge.catchUp(ge.LastUpdateTime.Sub(save.LastUpdateTime)); report != nil {
+			ge.showOfflineReport(report)
+		}
 	}
 
 	// No need to restart the engine since we didn't stop it
@@ -165,7 +254,7 @@ func (ge *GameEngine) LoadGame(filename string) error {
 
 // ListSaves returns a list of available save files
 func ListSaves() ([]string, error) {
-	saveDir := filepath.Join(".", "data", "saves")
+	saveDir := SaveDir()
 
 	// Check if directory exists
 	if _, err := os.Stat(saveDir); os.IsNotExist(err) {
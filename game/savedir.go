@@ -0,0 +1,171 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SaveDirEnv is the environment variable that overrides the save directory
+const SaveDirEnv = "CIVCLI_SAVE_DIR"
+
+// legacySaveDir is where saves were kept before the location was configurable,
+// relative to the directory the game was launched from
+var legacySaveDir = filepath.Join(".", "data", "saves")
+
+// legacyMigrationMarker is created in the save directory once old saves have
+// been looked for, so the migration only ever runs once
+const legacyMigrationMarker = ".legacy-migrated"
+
+// saveDirectory is the resolved save directory; empty until SetSaveDir is called
+var saveDirectory string
+
+// ResolveSaveDir picks the save directory. In order of precedence it uses
+// the given flag value, the CIVCLI_SAVE_DIR environment variable, and
+// $XDG_DATA_HOME/civcli/saves (defaulting to ~/.local/share/civcli/saves).
+func ResolveSaveDir(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if dir := os.Getenv(SaveDirEnv); dir != "" {
+		return dir, nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find a save directory (set --save-dir or %s): %w", SaveDirEnv, err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "civcli", "saves"), nil
+}
+
+// SetSaveDir sets the directory used for all save files
+func SetSaveDir(dir string) {
+	saveDirectory = dir
+}
+
+// SaveDir returns the directory holding save files. Until SetSaveDir is
+// called it falls back to the legacy ./data/saves directory.
+func SaveDir() string {
+	if saveDirectory == "" {
+		return legacySaveDir
+	}
+	return saveDirectory
+}
+
+// MigrateLegacySaves moves saves and exports from the old ./data/saves
+// directory into the configured save directory, the first time the game
+// runs with it. Only files that really are saves or exports are moved, and
+// files that already exist at the new location are left where they are. It
+// returns how many files were moved.
+func MigrateLegacySaves() (int, error) {
+	dir := SaveDir()
+
+	legacyAbs, err := filepath.Abs(legacySaveDir)
+	if err != nil {
+		return 0, err
+	}
+	dirAbs, err := filepath.Abs(dir)
+	if err != nil {
+		return 0, err
+	}
+	if legacyAbs == dirAbs {
+		return 0, nil
+	}
+
+	marker := filepath.Join(dir, legacyMigrationMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return 0, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create save directory: %w", err)
+	}
+
+	files, err := os.ReadDir(legacySaveDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read old save directory: %w", err)
+	}
+
+	moved := 0
+	for _, file := range files {
+		from := filepath.Join(legacySaveDir, file.Name())
+		if file.IsDir() || !isLegacySaveFile(from) {
+			continue
+		}
+
+		to := filepath.Join(dir, file.Name())
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+
+		if err := moveFile(from, to); err != nil {
+			return moved, fmt.Errorf("failed to move %s: %w", file.Name(), err)
+		}
+		moved++
+	}
+
+	// Tidy up the old directories if nothing else is left in them
+	os.Remove(legacySaveDir)
+	os.Remove(filepath.Dir(legacySaveDir))
+
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		return moved, fmt.Errorf("failed to record the save migration: %w", err)
+	}
+	return moved, nil
+}
+
+// isLegacySaveFile reports whether a file is a save or an export, judged by
+// its contents rather than just its extension
+func isLegacySaveFile(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	switch filepath.Ext(path) {
+	case ".json":
+		return isSaveData(data)
+	case ExportExtension:
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte(exportPrefix)) {
+			if data, err = DecodeExportString(string(data)); err != nil {
+				return false
+			}
+		}
+		save, err := decompressExport(data)
+		return err == nil && isSaveData(save)
+	}
+	return false
+}
+
+// isSaveData reports whether data looks like a save of any schema version:
+// a JSON object with the fields every save has
+func isSaveData(data []byte) bool {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return false
+	}
+	_, hasAge := raw["age"]
+	_, hasResources := raw["resources"]
+	return hasAge && hasResources
+}
+
+// moveFile renames a file, copying it when the destination is on another filesystem
+func moveFile(from, to string) error {
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(to, data); err != nil {
+		return err
+	}
+	return os.Remove(from)
+}
@@ -0,0 +1,282 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxWaitTicks bounds waits when ticks are simulated rather than
+	// following the clock, so a condition that never holds or a mistyped
+	// tick count can't hang a run
+	maxWaitTicks = 100000
+	// maxScriptDepth bounds scripts running other scripts with 'run'
+	maxScriptDepth = 8
+	// scriptPollInterval is how often a live script checks whether it can
+	// stop waiting
+	scriptPollInterval = 100 * time.Millisecond
+)
+
+// ScriptLine is a command or directive from a script file
+type ScriptLine struct {
+	Number int // Line number in the file, for error messages
+	Text   string
+}
+
+// Script is a list of commands run one after another. Besides the console
+// commands, scripts support the directives 'wait <ticks>', 'wait until
+// <condition>' and 'run <file>'. Anything after a '#' is a comment.
+type Script struct {
+	Name        string
+	Lines       []ScriptLine
+	StopOnError bool // Stop at the first command that fails instead of carrying on
+}
+
+// ReadScript reads a script file; "-" reads standard input
+func ReadScript(path string) (*Script, error) {
+	if path == "-" {
+		return ParseScript("stdin", os.Stdin)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open script: %w", err)
+	}
+	defer file.Close()
+	return ParseScript(path, file)
+}
+
+// ParseScript reads the commands of a script, skipping blank lines and comments
+func ParseScript(name string, r io.Reader) (*Script, error) {
+	script := &Script{Name: name}
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		script.Lines = append(script.Lines, ScriptLine{Number: number, Text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+	return script, nil
+}
+
+// scriptRunner executes scripts. A simulated runner is used while the game
+// clock isn't running (headless mode): it runs with the engine lock held and
+// waits by simulating ticks. A live runner runs alongside the game clock in
+// its own goroutine, taking the lock for each step and waiting in real time.
+type scriptRunner struct {
+	ge     *GameEngine
+	live   bool
+	cancel <-chan struct{} // Closed to stop a live script
+}
+
+// run executes a script, returning the error that stopped it, if any
+func (r *scriptRunner) run(script *Script, depth int) error {
+	if depth > maxScriptDepth {
+		return fmt.Errorf("Scripts are nested more than %d deep", maxScriptDepth)
+	}
+
+	for _, line := range script.Lines {
+		if r.stopped() {
+			return nil
+		}
+
+		err := r.step(line.Text, depth)
+		if err != nil && script.StopOnError {
+			return fmt.Errorf("%s:%d: %w", script.Name, line.Number, err)
+		}
+	}
+	return nil
+}
+
+// step runs a single script line
+func (r *scriptRunner) step(text string, depth int) error {
+	words := strings.Fields(text)
+	switch strings.ToLower(words[0]) {
+	case "wait":
+		r.locked(func() { r.ge.Display.ShowMessage("> "+text, "command") })
+		err := r.wait(words[1:])
+		if err != nil {
+			r.locked(func() { r.ge.Display.ShowMessage(err.Error(), "error") })
+		}
+		return err
+	case "run":
+		// Nested scripts run within this one rather than as a separate job
+		if len(words) >= 2 && !strings.EqualFold(words[1], "stop") {
+			r.locked(func() { r.ge.Display.ShowMessage("> "+text, "command") })
+			script, err := ReadScript(words[1])
+			if err == nil {
+				script.StopOnError = len(words) > 2 && strings.EqualFold(words[2], "stop-on-error")
+				err = r.run(script, depth+1)
+			}
+			if err != nil {
+				r.locked(func() { r.ge.Display.ShowMessage(err.Error(), "error") })
+			}
+			return err
+		}
+	}
+
+	var err error
+	r.locked(func() {
+		r.ge.Display.ShowMessage("> "+text, "command")
+		err = r.ge.Commands.Process(text)
+	})
+	return err
+}
+
+// wait handles 'wait <ticks>' and 'wait until <condition>'
+func (r *scriptRunner) wait(args []string) error {
+	if len(args) >= 2 && strings.EqualFold(args[0], "until") {
+		var condition *Condition
+		var err error
+		r.locked(func() { condition, err = r.ge.parseCondition(strings.Join(args[1:], " ")) })
+		if err != nil {
+			return fmt.Errorf("Invalid condition: %w", err)
+		}
+		return r.waitUntil(condition)
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: wait <ticks> | wait until <condition>")
+	}
+	ticks, err := strconv.Atoi(args[0])
+	if err != nil || ticks < 0 {
+		return fmt.Errorf("Ticks must be a non-negative number")
+	}
+	if ticks > maxWaitTicks {
+		return fmt.Errorf("Can't wait more than %d ticks", maxWaitTicks)
+	}
+	return r.waitTicks(ticks)
+}
+
+// waitTicks waits until the given number of ticks have passed
+func (r *scriptRunner) waitTicks(ticks int) error {
+	if !r.live {
+		for i := 0; i < ticks; i++ {
+			r.ge.updateSingleTick()
+		}
+		r.ge.LastUpdateTime = time.Now()
+		return nil
+	}
+
+	var target int
+	r.locked(func() { target = r.ge.Tick + ticks })
+	return r.poll(func() bool { return r.ge.Tick >= target })
+}
+
+// waitUntil waits until the condition holds
+func (r *scriptRunner) waitUntil(condition *Condition) error {
+	if !r.live {
+		for i := 0; i < maxWaitTicks; i++ {
+			if condition.evaluate(r.ge) {
+				r.ge.LastUpdateTime = time.Now()
+				return nil
+			}
+			r.ge.updateSingleTick()
+		}
+		return fmt.Errorf("Condition '%s' still not met after %d ticks", condition.Text, maxWaitTicks)
+	}
+
+	return r.poll(func() bool { return condition.evaluate(r.ge) })
+}
+
+// poll checks done with the engine lock held until it returns true or the
+// script is stopped, in which case the script's loop notices and ends
+func (r *scriptRunner) poll(done func() bool) error {
+	for {
+		finished := false
+		r.locked(func() { finished = done() })
+		if finished {
+			return nil
+		}
+		if r.stopped() {
+			return nil
+		}
+		time.Sleep(scriptPollInterval)
+	}
+}
+
+// stopped reports whether the script should stop: the game is quitting or
+// a live script was cancelled
+func (r *scriptRunner) stopped() bool {
+	select {
+	case <-r.cancel:
+		return true
+	default:
+	}
+
+	return !r.ge.Running.Load()
+}
+
+// locked runs f with the engine lock held. Simulated runners already hold it.
+func (r *scriptRunner) locked(f func()) {
+	if r.live {
+		r.ge.mu.Lock()
+		defer r.ge.mu.Unlock()
+	}
+	f()
+}
+
+// startScript runs a script in the background alongside the game clock. The
+// caller must hold the engine lock.
+func (ge *GameEngine) startScript(script *Script) error {
+	if ge.scriptCancel != nil {
+		return fmt.Errorf("A script is already running; use 'run stop' to stop it")
+	}
+
+	cancel := make(chan struct{})
+	ge.scriptCancel = cancel
+	ge.Display.ShowMessage("Running script "+script.Name+" ("+strconv.Itoa(len(script.Lines))+" lines)", "info")
+
+	go func() {
+		runner := &scriptRunner{ge: ge, live: true, cancel: cancel}
+		err := runner.run(script, 0)
+
+		ge.mu.Lock()
+		defer ge.mu.Unlock()
+		if ge.scriptCancel == cancel {
+			ge.scriptCancel = nil
+		}
+		select {
+		case <-cancel:
+			ge.Display.ShowMessage("Script "+script.Name+" stopped", "warning")
+		default:
+			if err != nil {
+				ge.Display.ShowMessage("Script stopped at "+err.Error(), "error")
+			} else {
+				ge.Display.ShowMessage("Script "+script.Name+" finished", "success")
+			}
+		}
+	}()
+	return nil
+}
+
+// stopScript cancels the running background script. The caller must hold
+// the engine lock.
+func (ge *GameEngine) stopScript() bool {
+	if ge.scriptCancel == nil {
+		return false
+	}
+	close(ge.scriptCancel)
+	ge.scriptCancel = nil
+	return true
+}
+
+// runScript runs a script to completion, simulating ticks for its waits.
+// The caller must hold the engine lock.
+func (ge *GameEngine) runScript(script *Script) error {
+	runner := &scriptRunner{ge: ge}
+	return runner.run(script, 0)
+}
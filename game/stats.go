@@ -21,6 +21,7 @@ type GameStats struct {
 	VillagersRecruited map[string]int   `json:"villagersRecruited"`
 	AgesReached       []string          `json:"agesReached"`
 	StartTime         time.Time         `json:"startTime"`
+	UnverifiedLoads   int               `json:"unverifiedLoads,omitempty"` // Times a modified save was loaded anyway
 }
 
 // NewGameStats creates a new game stats tracker
@@ -72,6 +73,12 @@ func (gs *GameStats) AddAgeReached(age string) {
 	gs.AgesReached = append(gs.AgesReached, age)
 }
 
+// AddUnverifiedLoad records that a save failing its integrity check was loaded
+func (gs *GameStats) AddUnverifiedLoad(tick int, filename string) {
+	gs.UnverifiedLoads++
+	gs.AddEvent(tick, "unverified_load", "Loaded modified save '"+filename+"' without verification")
+}
+
 // GetPlayTime returns the time played in hours and minutes
 func (gs *GameStats) GetPlayTime() string {
 	duration := time.Since(gs.StartTime)
@@ -106,3 +113,23 @@ func (gs *GameStats) GetTotalVillagersRecruited() int {
 	}
 	return total
 }
+
+// Clone returns a deep copy of the statistics
+func (gs *GameStats) Clone() *GameStats {
+	clone := *gs
+	clone.Events = append([]GameEvent{}, gs.Events...)
+	clone.AgesReached = append([]string{}, gs.AgesReached...)
+	clone.ResourcesGathered = make(map[string]float64, len(gs.ResourcesGathered))
+	for resource, amount := range gs.ResourcesGathered {
+		clone.ResourcesGathered[resource] = amount
+	}
+	clone.BuildingsBuilt = make(map[string]int, len(gs.BuildingsBuilt))
+	for building, count := range gs.BuildingsBuilt {
+		clone.BuildingsBuilt[building] = count
+	}
+	clone.VillagersRecruited = make(map[string]int, len(gs.VillagersRecruited))
+	for vtype, count := range gs.VillagersRecruited {
+		clone.VillagersRecruited[vtype] = count
+	}
+	return &clone
+}
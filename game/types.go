@@ -1,7 +1,10 @@
 package game
 
+import "sort"
+
 // GameState represents the essential state of the game that can be accessed by the UI
 type GameState struct {
+	CivName     string
 	Age         string
 	Tick        int
 	Resources   map[string]float64
@@ -13,10 +16,24 @@ type GameState struct {
 		Progress   float64
 		Cost       float64
 		Researched []string
+		Queue      []string // Technologies to research next, in order
 	}
-	TickDurationSeconds float64  // Add tick duration (seconds per tick) for UI display
-	foodSources         []string // List of resources that count as food
-	TotalFood           float64  // Total amount of food from all food sources
+	TickDurationSeconds float64            // Add tick duration (seconds per tick) for UI display
+	Speed               float64            // Game speed multiplier
+	Paused              bool               // Whether the game clock is paused
+	foodSources         []string           // List of resources that count as food
+	TotalFood           float64            // Total amount of food from all food sources
+	Rates               map[string]float64 // Effective production per tick, including research bonuses
+	FoodRate            float64            // Net food per tick after villagers eat
+	BuildQueue          []QueuedBuild      // Build orders, front of the queue first
+	Construction        []ConstructionSite // Buildings under construction, in the order they are worked on
+	Builders            int                // Villagers assigned to construction
+}
+
+// QueuedBuild is a build order as shown on the dashboard
+type QueuedBuild struct {
+	BuildOrder
+	Cost map[string]float64 // Cost of the order's next building
 }
 
 // GetTotalFood returns the sum of all food resources
@@ -52,8 +69,13 @@ type GameStateProvider interface {
 // Ensure GameEngine implements GameStateProvider
 var _ GameStateProvider = (*GameEngine)(nil)
 
-// GetGameState returns the current game state for UI rendering
+// GetGameState returns a snapshot of the current game state for UI rendering.
+// The snapshot shares no maps with the engine, so it is safe to read while the
+// simulation keeps running.
 func (ge *GameEngine) GetGameState() GameState {
+	ge.mu.Lock()
+	defer ge.mu.Unlock()
+
 	// Get research info
 	currentResearch, progress, cost := ge.Research.GetProgress()
 
@@ -63,9 +85,11 @@ func (ge *GameEngine) GetGameState() GameState {
 	for name := range researchedTechs {
 		researched = append(researched, name)
 	}
+	sort.Strings(researched)
 
 	// Create GameState with food sources
 	gameState := GameState{
+		CivName:     ge.CivName,
 		Age:         ge.Age,
 		Tick:        ge.Tick,
 		Resources:   ge.Resources.GetAll(),
@@ -77,15 +101,41 @@ func (ge *GameEngine) GetGameState() GameState {
 			Progress   float64
 			Cost       float64
 			Researched []string
+			Queue      []string // Technologies to research next, in order
 		}{
 			Current:    currentResearch,
 			Progress:   progress,
 			Cost:       cost,
 			Researched: researched,
+			Queue:      ge.Research.GetQueue(),
 		},
-		TickDurationSeconds: ge.TickDuration.Seconds(),   // Pass tick duration to UI
+		TickDurationSeconds: ge.TickDuration.Seconds(), // Pass tick duration to UI
+		Speed:               ge.Speed,
+		Paused:              ge.Paused,
 		TotalFood:           ge.Resources.GetTotalFood(), // Pass total food value separately
 	}
+	gameState.Rates, gameState.FoodRate = ge.productionRates()
+	gameState.Construction = ge.Buildings.GetConstruction()
+	gameState.Builders = ge.Villagers.GetAssigned(ConstructionTask)
+	for _, order := range ge.Queue.GetAll() {
+		gameState.BuildQueue = append(gameState.BuildQueue, QueuedBuild{
+			BuildOrder: order,
+			Cost:       ge.Buildings.GetCost(order.Building),
+		})
+	}
 
 	return gameState
 }
+
+// productionRates simulates one tick of production into a scratch resource
+// manager, so the rates shown match what the simulation actually does
+func (ge *GameEngine) productionRates() (map[string]float64, float64) {
+	scratch := NewResourceManager(ge.Content)
+	ge.Villagers.gatherAllResourcesAndTrack(scratch, ge.Buildings, NewGameStats(), 1)
+	ge.Buildings.Update(scratch, 1)
+	for resource, amount := range ge.modifiers.FlatBonuses {
+		scratch.Add(resource, amount)
+	}
+
+	return scratch.GetAll(), scratch.GetTotalFood() - ge.Villagers.GetFoodConsumption()
+}
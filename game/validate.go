@@ -0,0 +1,467 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ContentIssue describes a single problem found in a content pack
+type ContentIssue struct {
+	Location string // file:key path of the offending entry, e.g. pack.json:technologies.Bows.prerequisites[0]
+	Message  string
+}
+
+// String formats the issue as "location: message"
+func (ci ContentIssue) String() string {
+	return ci.Location + ": " + ci.Message
+}
+
+// ContentError is returned when a content pack fails validation
+type ContentError struct {
+	Issues []ContentIssue
+}
+
+// Error lists every issue on its own line
+func (ce *ContentError) Error() string {
+	lines := make([]string, 0, len(ce.Issues)+1)
+	lines = append(lines, fmt.Sprintf("content pack has %d problem(s):", len(ce.Issues)))
+	for _, issue := range ce.Issues {
+		lines = append(lines, "  "+issue.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// contentValidator accumulates issues while walking a content pack
+type contentValidator struct {
+	pack   *ContentPack
+	source string
+	issues []ContentIssue
+}
+
+// ValidateContent checks a content pack for dangling references, cycles in
+// technology prerequisites, unreachable ages and unbuildable items. The
+// source name (usually the pack's file name) prefixes every issue location.
+func ValidateContent(pack *ContentPack, source string) []ContentIssue {
+	cv := &contentValidator{pack: pack, source: source}
+
+	cv.validateResources()
+	cv.validateBuildings()
+	cv.validateVillagers()
+	cv.validateTechnologies()
+	cv.validateAges()
+	cv.validateStart()
+
+	sort.Slice(cv.issues, func(i, j int) bool {
+		return cv.issues[i].Location < cv.issues[j].Location
+	})
+	return cv.issues
+}
+
+// addIssue records a problem at the given key path
+func (cv *contentValidator) addIssue(key string, format string, args ...interface{}) {
+	cv.issues = append(cv.issues, ContentIssue{
+		Location: cv.source + ":" + key,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// isResource reports whether name is a defined resource or the generic "food"
+func (cv *contentValidator) isResource(name string) bool {
+	if name == "food" {
+		return len(cv.pack.FoodSources()) > 0
+	}
+	_, exists := cv.pack.Resources[name]
+	return exists
+}
+
+// isProducible reports whether some villager task or building effect yields the resource
+func (cv *contentValidator) isProducible(name string) bool {
+	if name == "food" {
+		for _, source := range cv.pack.FoodSources() {
+			if cv.isProducible(source) {
+				return true
+			}
+		}
+	}
+	for _, def := range cv.pack.Villagers {
+		for _, task := range def.Tasks {
+			if task == name {
+				return true
+			}
+		}
+	}
+	for _, def := range cv.pack.Buildings {
+		if amount, exists := def.Effects[name]; exists && amount > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ageIndex returns the position of an age, or -1 if it is not defined
+func (cv *contentValidator) ageIndex(name string) int {
+	for i, age := range cv.pack.Ages {
+		if age.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// buildingUnlockAge returns the index of the first age unlocking a building,
+// either directly or through a technology of that age, or -1 if nothing does
+func (cv *contentValidator) buildingUnlockAge(building string) int {
+	first := -1
+	for i, age := range cv.pack.Ages {
+		for _, b := range age.Unlocks.Buildings {
+			if b == building && (first < 0 || i < first) {
+				first = i
+			}
+		}
+	}
+	for _, tech := range cv.pack.Technologies {
+		for _, effect := range tech.Effects {
+			if effect.Type == EffectUnlockBuilding && effect.Building == building {
+				if i := cv.ageIndex(tech.Age); i >= 0 && (first < 0 || i < first) {
+					first = i
+				}
+			}
+		}
+	}
+	return first
+}
+
+func (cv *contentValidator) validateResources() {
+	if len(cv.pack.Resources) == 0 {
+		cv.addIssue("resources", "no resources are defined")
+	}
+	for name, def := range cv.pack.Resources {
+		if def.CollectionRate < 0 {
+			cv.addIssue("resources."+name+".collectionRate", "collection rate must not be negative")
+		}
+	}
+
+	if def, exists := cv.pack.Resources[cv.pack.DefaultFood]; !exists {
+		cv.addIssue("defaultFood", "unknown resource %q", cv.pack.DefaultFood)
+	} else if !def.Food {
+		cv.addIssue("defaultFood", "resource %q is not marked as food", cv.pack.DefaultFood)
+	}
+}
+
+func (cv *contentValidator) validateBuildings() {
+	for name, def := range cv.pack.Buildings {
+		key := "buildings." + name
+
+		for resource, amount := range def.Cost {
+			if !cv.isResource(resource) {
+				cv.addIssue(key+".cost."+resource, "unknown resource %q; the building can never be afforded", resource)
+			} else if !cv.isProducible(resource) {
+				cv.addIssue(key+".cost."+resource, "nothing produces %q; the building can never be afforded", resource)
+			}
+			if amount < 0 {
+				cv.addIssue(key+".cost."+resource, "cost must not be negative")
+			}
+		}
+
+		for effect := range def.Effects {
+			if effect != "villager_capacity" && !cv.isResource(effect) {
+				cv.addIssue(key+".effects."+effect, "unknown resource %q", effect)
+			}
+		}
+
+		for villagerType, bonuses := range def.RateBonuses {
+			if _, exists := cv.pack.Villagers[villagerType]; !exists {
+				cv.addIssue(key+".rateBonuses."+villagerType, "unknown villager type %q", villagerType)
+			}
+			for resource := range bonuses {
+				if !cv.isResource(resource) {
+					cv.addIssue(key+".rateBonuses."+villagerType+"."+resource, "unknown resource %q", resource)
+				}
+			}
+		}
+
+		if def.BuildTime < 0 {
+			cv.addIssue(key+".buildTime", "build time must not be negative")
+		} else if def.BuildTime > 0 && !cv.hasBuilders() {
+			cv.addIssue(key+".buildTime", "no villager type has the %q task, so the building can never be finished", ConstructionTask)
+		}
+
+		if def.UpgradeFrom != "" {
+			cv.validateUpgrade(name, def.UpgradeFrom)
+		}
+
+		if cv.buildingUnlockAge(name) < 0 {
+			cv.addIssue(key, "building is never unlocked by any age or technology and can never be built")
+		}
+	}
+}
+
+// validateUpgrade checks that a tier upgrades a defined building, that no
+// other building upgrades the same one and that the chain of upgrades
+// doesn't loop
+func (cv *contentValidator) validateUpgrade(name, base string) {
+	key := "buildings." + name + ".upgradeFrom"
+	if _, exists := cv.pack.Buildings[base]; !exists {
+		cv.addIssue(key, "unknown building %q", base)
+		return
+	}
+
+	others := []string{}
+	for other, def := range cv.pack.Buildings {
+		if other != name && def.UpgradeFrom == base {
+			others = append(others, other)
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		cv.addIssue(key, "%q is also upgraded to %s; a building can only have one upgrade", base, strings.Join(others, ", "))
+	}
+
+	for steps, current := 0, base; current != "" && steps <= len(cv.pack.Buildings); steps++ {
+		if current == name {
+			cv.addIssue(key, "upgrade chain loops back to %q", name)
+			return
+		}
+		current = cv.pack.Buildings[current].UpgradeFrom
+	}
+}
+
+// hasBuilders reports whether some villager type can work on construction
+func (cv *contentValidator) hasBuilders() bool {
+	for _, def := range cv.pack.Villagers {
+		for _, task := range def.Tasks {
+			if task == ConstructionTask {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cv *contentValidator) validateVillagers() {
+	for name, def := range cv.pack.Villagers {
+		key := "villagers." + name
+
+		tasks := make(map[string]bool)
+		for i, task := range def.Tasks {
+			tasks[task] = true
+			if task == ConstructionTask {
+				continue
+			}
+			if _, exists := cv.pack.Resources[task]; !exists {
+				cv.addIssue(fmt.Sprintf("%s.tasks[%d]", key, i), "unknown resource %q", task)
+			}
+		}
+		for resource := range def.GatherMultipliers {
+			if !tasks[resource] {
+				cv.addIssue(key+".gatherMultipliers."+resource, "%q is not one of this villager type's tasks", resource)
+			}
+		}
+		if def.FoodCost < 0 {
+			cv.addIssue(key+".foodCost", "food cost must not be negative")
+		}
+
+		_, startsWith := cv.pack.Start.Villagers[name]
+		if !startsWith && !cv.villagerUnlocked(name) {
+			cv.addIssue(key, "villager type is never unlocked by any age or technology and can never be recruited")
+		}
+	}
+}
+
+// villagerUnlocked reports whether any age or technology unlocks the villager type
+func (cv *contentValidator) villagerUnlocked(villagerType string) bool {
+	for _, age := range cv.pack.Ages {
+		for _, v := range age.Unlocks.Villagers {
+			if v == villagerType {
+				return true
+			}
+		}
+	}
+	for _, tech := range cv.pack.Technologies {
+		for _, effect := range tech.Effects {
+			if effect.Type == EffectUnlockVillager && effect.Villager == villagerType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cv *contentValidator) validateTechnologies() {
+	for name, tech := range cv.pack.Technologies {
+		key := "technologies." + name
+
+		if cv.ageIndex(tech.Age) < 0 {
+			cv.addIssue(key+".age", "unknown age %q", tech.Age)
+		}
+		if tech.Cost <= 0 {
+			cv.addIssue(key+".cost", "cost must be positive")
+		}
+		for i, prereq := range tech.Prerequisites {
+			if _, exists := cv.pack.Technologies[prereq]; !exists {
+				cv.addIssue(fmt.Sprintf("%s.prerequisites[%d]", key, i), "unknown technology %q", prereq)
+			}
+		}
+		for i, effect := range tech.Effects {
+			cv.validateEffect(fmt.Sprintf("%s.effects[%d]", key, i), effect)
+		}
+	}
+
+	cv.validateTechCycles()
+}
+
+// validateEffect checks that a technology effect is well-formed and references known content
+func (cv *contentValidator) validateEffect(key string, effect TechEffect) {
+	switch effect.Type {
+	case EffectProductionMultiplier:
+		if effect.Resource != AllTargets && !cv.isResource(effect.Resource) {
+			cv.addIssue(key+".resource", "unknown resource %q", effect.Resource)
+		}
+	case EffectFlatBonus:
+		if !cv.isResource(effect.Resource) {
+			cv.addIssue(key+".resource", "unknown resource %q", effect.Resource)
+		}
+	case EffectUnlockBuilding:
+		if _, exists := cv.pack.Buildings[effect.Building]; !exists {
+			cv.addIssue(key+".building", "unknown building %q", effect.Building)
+		}
+	case EffectUnlockVillager:
+		if _, exists := cv.pack.Villagers[effect.Villager]; !exists {
+			cv.addIssue(key+".villager", "unknown villager type %q", effect.Villager)
+		}
+	case EffectCapacity:
+		if effect.Value < 1 {
+			cv.addIssue(key+".value", "capacity increase must be at least 1")
+		}
+	case EffectCostReduction:
+		if _, exists := cv.pack.Buildings[effect.Building]; !exists && effect.Building != AllTargets {
+			cv.addIssue(key+".building", "unknown building %q", effect.Building)
+		}
+		if effect.Value <= 0 || effect.Value >= 1 {
+			cv.addIssue(key+".value", "cost reduction must be between 0 and 1")
+		}
+	default:
+		cv.addIssue(key+".type", "unknown effect type %q", effect.Type)
+	}
+}
+
+// validateTechCycles reports every cycle in the technology prerequisite graph
+func (cv *contentValidator) validateTechCycles() {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int)
+
+	names := make([]string, 0, len(cv.pack.Technologies))
+	for name := range cv.pack.Technologies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var path []string
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, prereq := range cv.pack.Technologies[name].Prerequisites {
+			if _, exists := cv.pack.Technologies[prereq]; !exists {
+				continue
+			}
+			switch state[prereq] {
+			case visiting:
+				// Found a cycle: report it from the first repeated technology
+				start := 0
+				for i, n := range path {
+					if n == prereq {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]string{}, path[start:]...), prereq)
+				cv.addIssue("technologies."+prereq+".prerequisites",
+					"prerequisite cycle %s; none of these can ever be researched", strings.Join(cycle, " -> "))
+			case unvisited:
+				visit(prereq)
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = done
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+}
+
+func (cv *contentValidator) validateAges() {
+	if len(cv.pack.Ages) == 0 {
+		cv.addIssue("ages", "no ages are defined")
+		return
+	}
+
+	seen := make(map[string]bool)
+	for i, age := range cv.pack.Ages {
+		key := fmt.Sprintf("ages[%d]", i)
+
+		if age.Name == "" {
+			cv.addIssue(key+".name", "age has no name")
+		} else if seen[age.Name] {
+			cv.addIssue(key+".name", "duplicate age %q", age.Name)
+		}
+		seen[age.Name] = true
+
+		for resource := range age.Requirements.Resources {
+			if !cv.isResource(resource) {
+				cv.addIssue(key+".requirements.resources."+resource, "unknown resource %q; %s is unreachable", resource, age.Name)
+			} else if !cv.isProducible(resource) {
+				cv.addIssue(key+".requirements.resources."+resource, "nothing produces %q; %s is unreachable", resource, age.Name)
+			}
+		}
+		for building := range age.Requirements.Buildings {
+			if _, exists := cv.pack.Buildings[building]; !exists {
+				cv.addIssue(key+".requirements.buildings."+building, "unknown building %q; %s is unreachable", building, age.Name)
+				continue
+			}
+			// The building has to be available before this age is reached
+			if unlockedAt := cv.buildingUnlockAge(building); unlockedAt < 0 || unlockedAt >= i {
+				cv.addIssue(key+".requirements.buildings."+building, "building %q is not unlocked before %s; the age is unreachable", building, age.Name)
+			}
+		}
+
+		for j, building := range age.Unlocks.Buildings {
+			if _, exists := cv.pack.Buildings[building]; !exists {
+				cv.addIssue(fmt.Sprintf("%s.unlocks.buildings[%d]", key, j), "unknown building %q", building)
+			}
+		}
+		for j, resource := range age.Unlocks.Resources {
+			if !cv.isResource(resource) {
+				cv.addIssue(fmt.Sprintf("%s.unlocks.resources[%d]", key, j), "unknown resource %q", resource)
+			}
+		}
+		for j, villagerType := range age.Unlocks.Villagers {
+			if _, exists := cv.pack.Villagers[villagerType]; !exists {
+				cv.addIssue(fmt.Sprintf("%s.unlocks.villagers[%d]", key, j), "unknown villager type %q", villagerType)
+			}
+		}
+	}
+}
+
+func (cv *contentValidator) validateStart() {
+	for resource := range cv.pack.Start.Resources {
+		if !cv.isResource(resource) {
+			cv.addIssue("start.resources."+resource, "unknown resource %q", resource)
+		}
+	}
+	for villagerType := range cv.pack.Start.Villagers {
+		if _, exists := cv.pack.Villagers[villagerType]; !exists {
+			cv.addIssue("start.villagers."+villagerType, "unknown villager type %q", villagerType)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package game
 
+import "sort"
+
 // VillagerAssignment represents assignment of villagers to tasks
 type VillagerAssignment map[string]int
 
@@ -12,42 +14,42 @@ type VillagerType struct {
 
 // VillagerManager handles villager creation and assignment
 type VillagerManager struct {
-	villagers map[string]*VillagerType
+	villagers   map[string]*VillagerType
+	definitions map[string]VillagerDef
+	modifiers   *ResearchModifiers
 }
 
-// NewVillagerManager creates a new villager manager
-func NewVillagerManager() *VillagerManager {
+// NewVillagerManager creates a new villager manager from the content pack
+func NewVillagerManager(content *ContentPack) *VillagerManager {
 	vm := &VillagerManager{
-		villagers: make(map[string]*VillagerType),
+		villagers:   make(map[string]*VillagerType),
+		definitions: make(map[string]VillagerDef),
+		modifiers:   NewResearchModifiers(),
 	}
 
-	// Initialize default villager types
-	vm.villagers["villager"] = &VillagerType{
-		Count:    0,
-		FoodCost: 0.5,
-		Assignment: VillagerAssignment{
-			"foraging":  0,
-			"wood":      0,
-			"stone":     0,
-			"gold":      0,
-			"knowledge": 0,
-			"hunting":   0,
-			"idle":      0,
-		},
-	}
+	// Initialize villager types with every task idle
+	for vtype, def := range content.Villagers {
+		assignment := VillagerAssignment{"idle": 0}
+		for _, task := range def.Tasks {
+			assignment[task] = 0
+		}
 
-	vm.villagers["scholar"] = &VillagerType{
-		Count:    0,
-		FoodCost: 0.75,
-		Assignment: VillagerAssignment{
-			"knowledge": 0,
-			"idle":      0,
-		},
+		vm.definitions[vtype] = def
+		vm.villagers[vtype] = &VillagerType{
+			Count:      0,
+			FoodCost:   def.FoodCost,
+			Assignment: assignment,
+		}
 	}
 
 	return vm
 }
 
+// ApplyResearch sets the research modifiers used when gathering
+func (vm *VillagerManager) ApplyResearch(modifiers *ResearchModifiers) {
+	vm.modifiers = modifiers
+}
+
 // Add adds new villagers
 func (vm *VillagerManager) Add(villagerType string, count int) bool {
 	if v, exists := vm.villagers[villagerType]; exists {
@@ -145,18 +147,49 @@ func (vm *VillagerManager) GetCount(villagerType string) int {
 	return 0
 }
 
-// GetAll returns all villagers and their info
+// GetAssigned returns how many villagers of all types work on a task
+func (vm *VillagerManager) GetAssigned(task string) int {
+	total := 0
+	for _, v := range vm.villagers {
+		total += v.Assignment[task]
+	}
+	return total
+}
+
+// GetTasks returns the resources a villager type can be assigned to
+func (vm *VillagerManager) GetTasks(villagerType string) []string {
+	v, exists := vm.villagers[villagerType]
+	if !exists {
+		return nil
+	}
+
+	tasks := make([]string, 0, len(v.Assignment))
+	for task := range v.Assignment {
+		if task != "idle" {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Strings(tasks)
+	return tasks
+}
+
+// VillagerInfo is a read-only view of a villager type and its assignments
 type VillagerInfo struct {
 	Count      int
 	Assignment map[string]int
 }
 
+// GetAll returns a copy of all villagers and their info
 func (vm *VillagerManager) GetAll() map[string]VillagerInfo {
 	result := make(map[string]VillagerInfo)
 	for vtype, v := range vm.villagers {
+		assignment := make(map[string]int, len(v.Assignment))
+		for resource, count := range v.Assignment {
+			assignment[resource] = count
+		}
 		result[vtype] = VillagerInfo{
 			Count:      v.Count,
-			Assignment: v.Assignment,
+			Assignment: assignment,
 		}
 	}
 	return result
@@ -171,174 +204,72 @@ func (vm *VillagerManager) GetFoodConsumption() float64 {
 	return total
 }
 
-// CollectResources collects resources based on villager assignments
+// CollectResources collects resources for a single tick based on villager assignments
 func (vm *VillagerManager) CollectResources(rm *ResourceManager, bm *BuildingManager) {
-	// First, collect all resources by villager type and assignment
-	vm.gatherAllResources(rm, bm)
-
-	// Then consume food from the total food pool
-	foodConsumption := vm.GetFoodConsumption()
-	rm.RemoveFood(foodConsumption)
+	vm.CollectResourcesAndTrack(rm, NewGameStats(), bm, 1)
 }
 
-// gatherAllResources handles the resource gathering for all villager types
-func (vm *VillagerManager) gatherAllResources(rm *ResourceManager, bm *BuildingManager) {
-	for vtype, v := range vm.villagers {
-		for resource, count := range v.Assignment {
-			if resource == "idle" || count <= 0 {
-				continue
-			}
-
-			// Get the base collection rate for this resource
-			baseRate := rm.GetCollectionRate(resource)
-
-			// Calculate the final amount based on resource type
-			switch resource {
-			case "knowledge":
-				vm.gatherKnowledge(rm, bm, vtype, count, baseRate)
-			case "hunting":
-				vm.gatherHunting(rm, bm, vtype, count, baseRate)
-			default:
-				vm.gatherStandardResource(rm, bm, vtype, resource, count, baseRate)
-			}
-		}
-	}
-}
-
-// gatherKnowledge handles specialized knowledge gathering with different rates by villager type
-func (vm *VillagerManager) gatherKnowledge(rm *ResourceManager, bm *BuildingManager, vtype string, count int, baseRate float64) {
-	// Apply villager-specific knowledge gathering modifiers
-	modifiedRate := baseRate
-	if vtype == "villager" {
-		// Regular villagers gather knowledge at 20% of the normal rate
-		modifiedRate *= 0.2
-	} else if vtype == "scholar" {
-		// Scholars gather knowledge at 150% of the normal rate
-		modifiedRate *= 1.5
-	}
-
-	// Apply building bonuses
-	buildingBonus := bm.GetCollectionRateBonus(vtype, "knowledge")
-	modifiedRate *= (1.0 + buildingBonus)
-
-	// Calculate final amount and add the resource
-	amount := float64(count) * modifiedRate
-	rm.Add("knowledge", amount)
-}
-
-// gatherHunting handles hunting which provides both hunting resource and food bonus
-func (vm *VillagerManager) gatherHunting(rm *ResourceManager, bm *BuildingManager, vtype string, count int, baseRate float64) {
-	// Apply building bonuses to hunting rate
-	buildingBonus := bm.GetCollectionRateBonus(vtype, "hunting")
-	modifiedRate := baseRate * (1.0 + buildingBonus)
-
-	// Calculate hunting resource amount
-	amount := float64(count) * modifiedRate
-	rm.Add("hunting", amount)
-
-	// Add bonus food from hunting (40% of hunting collection)
-	foodBonus := baseRate * 0.4 * float64(count)
-	rm.Add("food", foodBonus)
-}
-
-// gatherStandardResource handles standard resource gathering without special rules
-func (vm *VillagerManager) gatherStandardResource(rm *ResourceManager, bm *BuildingManager, vtype string, resource string, count int, baseRate float64) {
-	// Apply building bonuses
-	buildingBonus := bm.GetCollectionRateBonus(vtype, resource)
-	modifiedRate := baseRate * (1.0 + buildingBonus)
-
-	// Calculate final amount and add the resource
-	amount := float64(count) * modifiedRate
-	rm.Add(resource, amount)
-}
-
-// CollectResourcesAndTrack collects resources based on villager assignments and tracks statistics
-func (vm *VillagerManager) CollectResourcesAndTrack(rm *ResourceManager, stats *GameStats, bm *BuildingManager) {
+// CollectResourcesAndTrack collects resources for the given number of ticks based on villager
+// assignments, tracks statistics, and returns the amount of food consumed
+func (vm *VillagerManager) CollectResourcesAndTrack(rm *ResourceManager, stats *GameStats, bm *BuildingManager, ticks int) float64 {
 	// Use the refactored resource gathering approach while tracking statistics
-	vm.gatherAllResourcesAndTrack(rm, bm, stats)
+	vm.gatherAllResourcesAndTrack(rm, bm, stats, float64(ticks))
 
 	// Then consume food from the total food pool
-	foodConsumption := vm.GetFoodConsumption()
-	rm.RemoveFood(foodConsumption)
+	foodConsumption := vm.GetFoodConsumption() * float64(ticks)
+	if !rm.RemoveFood(foodConsumption) {
+		return 0
+	}
+	return foodConsumption
 }
 
 // gatherAllResourcesAndTrack handles resource gathering with statistics tracking
-func (vm *VillagerManager) gatherAllResourcesAndTrack(rm *ResourceManager, bm *BuildingManager, stats *GameStats) {
+func (vm *VillagerManager) gatherAllResourcesAndTrack(rm *ResourceManager, bm *BuildingManager, stats *GameStats, ticks float64) {
 	for vtype, v := range vm.villagers {
 		for resource, count := range v.Assignment {
-			if resource == "idle" || count <= 0 {
+			// Builders produce no resources; their work goes into construction
+			if resource == "idle" || resource == ConstructionTask || count <= 0 {
 				continue
 			}
 
-			// Get the base collection rate for this resource
-			baseRate := rm.GetCollectionRate(resource)
-
-			// Calculate the final amount based on resource type and track statistics
-			switch resource {
-			case "knowledge":
-				amount := vm.gatherKnowledgeWithTracking(rm, bm, vtype, count, baseRate, stats)
-				stats.AddResourceGathered(resource, amount)
-			case "hunting":
-				huntingAmount, foodAmount := vm.gatherHuntingWithTracking(rm, bm, vtype, count, baseRate)
-				stats.AddResourceGathered(resource, huntingAmount)
+			amount, foodAmount := vm.gatherResourceWithTracking(rm, bm, vtype, resource, count, ticks)
+			stats.AddResourceGathered(resource, amount)
+			if foodAmount > 0 {
 				stats.AddResourceGathered("food", foodAmount)
-			default:
-				amount := vm.gatherStandardResourceWithTracking(rm, bm, vtype, resource, count, baseRate)
-				stats.AddResourceGathered(resource, amount)
 			}
 		}
 	}
 }
 
-// gatherKnowledgeWithTracking handles specialized knowledge gathering with tracking
-func (vm *VillagerManager) gatherKnowledgeWithTracking(rm *ResourceManager, bm *BuildingManager, vtype string, count int, baseRate float64, stats *GameStats) float64 {
-	// Apply villager-specific knowledge gathering modifiers
+// gatherResourceWithTracking gathers a resource for one villager type and returns the amount
+// gathered plus any bonus food (e.g. from hunting)
+func (vm *VillagerManager) gatherResourceWithTracking(rm *ResourceManager, bm *BuildingManager, vtype string, resource string, count int, ticks float64) (float64, float64) {
+	// Get the base collection rate for this resource
+	baseRate := rm.GetCollectionRate(resource)
+
+	// Apply villager-specific gathering modifiers (e.g. scholars excel at knowledge)
 	modifiedRate := baseRate
-	if vtype == "villager" {
-		// Regular villagers gather knowledge at 20% of the normal rate
-		modifiedRate *= 0.2
-	} else if vtype == "scholar" {
-		// Scholars gather knowledge at 150% of the normal rate
-		modifiedRate *= 1.5
+	if multiplier, exists := vm.definitions[vtype].GatherMultipliers[resource]; exists {
+		modifiedRate *= multiplier
 	}
 
 	// Apply building bonuses
-	buildingBonus := bm.GetCollectionRateBonus(vtype, "knowledge")
+	buildingBonus := bm.GetCollectionRateBonus(vtype, resource)
 	modifiedRate *= (1.0 + buildingBonus)
 
-	// Calculate final amount and add the resource
-	amount := float64(count) * modifiedRate
-	rm.Add("knowledge", amount)
-
-	return amount
-}
-
-// gatherHuntingWithTracking handles hunting which provides both hunting resource and food bonus with tracking
-func (vm *VillagerManager) gatherHuntingWithTracking(rm *ResourceManager, bm *BuildingManager, vtype string, count int, baseRate float64) (float64, float64) {
-	// Apply building bonuses to hunting rate
-	buildingBonus := bm.GetCollectionRateBonus(vtype, "hunting")
-	modifiedRate := baseRate * (1.0 + buildingBonus)
-
-	// Calculate hunting resource amount
-	huntingAmount := float64(count) * modifiedRate
-	rm.Add("hunting", huntingAmount)
-
-	// Add bonus food from hunting (40% of hunting collection)
-	foodBonus := baseRate * 0.4 * float64(count)
-	rm.Add("food", foodBonus)
-
-	return huntingAmount, foodBonus
-}
-
-// gatherStandardResourceWithTracking handles standard resource gathering with tracking
-func (vm *VillagerManager) gatherStandardResourceWithTracking(rm *ResourceManager, bm *BuildingManager, vtype string, resource string, count int, baseRate float64) float64 {
-	// Apply building bonuses
-	buildingBonus := bm.GetCollectionRateBonus(vtype, resource)
-	modifiedRate := baseRate * (1.0 + buildingBonus)
+	// Apply research production bonuses
+	modifiedRate *= vm.modifiers.ProductionMultiplier(resource)
 
 	// Calculate final amount and add the resource
-	amount := float64(count) * modifiedRate
+	amount := float64(count) * modifiedRate * ticks
 	rm.Add(resource, amount)
 
-	return amount
+	// Some resources yield bonus food as a fraction of the base rate
+	foodBonus := baseRate * rm.GetFoodBonus(resource) * float64(count) * ticks
+	foodBonus *= vm.modifiers.ProductionMultiplier("food")
+	if foodBonus > 0 {
+		rm.Add("food", foodBonus)
+	}
+
+	return amount, foodBonus
 }
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
@@ -11,6 +13,70 @@ import (
 )
 
 func main() {
+	// Subcommands are handled before the regular game flags
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		os.Exit(runDiff(os.Args[2:]))
+	}
+
+	offlineEfficiency := flag.Float64("offline-efficiency", game.DefaultOfflineEfficiency, "fraction of missed ticks simulated while you were away")
+	maxOffline := flag.Duration("max-offline", game.DefaultMaxOfflineDuration, "longest absence that will be caught up (e.g. 8h)")
+	headless := flag.Bool("headless", false, "run the simulation without the terminal UI and print a JSON state report")
+	ticks := flag.Int("ticks", 100, "number of ticks to simulate in headless mode")
+	script := flag.String("script", "", "file of commands to run at startup ('-' reads stdin in headless mode)")
+	stopOnError := flag.Bool("stop-on-error", false, "stop the script at the first command that fails")
+	load := flag.String("load", "", "save to load before running in headless mode")
+	autosave := flag.Duration("autosave", game.DefaultAutosaveInterval, "how often to autosave (0 disables periodic autosaves)")
+	autosaveSlots := flag.Int("autosave-slots", game.DefaultAutosaveSlots, "number of rotating autosave slots to keep")
+	saveDir := flag.String("save-dir", "", "directory for save files (default $"+game.SaveDirEnv+" or $XDG_DATA_HOME/civcli/saves)")
+	saveKeyPath := flag.String("save-key", "", "file holding a local key used to sign saves (created if missing)")
+	historySize := flag.Int("history-size", game.DefaultHistorySize, "number of commands kept in the command history (0 disables it)")
+	contentPath := flag.String("content", "", "content pack file overriding the built-in buildings, technologies and ages")
+	flag.Parse()
+
+	content, err := loadContent(*contentPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading content: %v\n", err)
+		os.Exit(1)
+	}
+
+	resolvedSaveDir, err := game.ResolveSaveDir(*saveDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	game.SetSaveDir(resolvedSaveDir)
+
+	if *saveKeyPath != "" {
+		key, err := game.LoadSaveKey(*saveKeyPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		game.SetSaveKey(key)
+	}
+
+	// Saves used to live in ./data/saves; move any left there to the new location
+	if moved, err := game.MigrateLegacySaves(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to migrate old saves: %v\n", err)
+	} else if moved > 0 {
+		fmt.Fprintf(os.Stderr, "Moved %d save file(s) from ./data/saves to %s\n", moved, resolvedSaveDir)
+	}
+
+	if *headless {
+		gameEngine := game.NewGameEngineWithContent(game.NewLogDisplay(os.Stderr), content)
+		gameEngine.OfflineEfficiency = *offlineEfficiency
+		gameEngine.MaxOfflineDuration = *maxOffline
+
+		if err := runHeadless(gameEngine, *ticks, *script, *stopOnError, *load); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running headless simulation: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	fmt.Println("CivIdleCli - A Command Line Civilization Builder")
 
 	// Set up signal handling for clean exit
@@ -21,7 +87,40 @@ func main() {
 	uiManager := ui.NewUIManager()
 
 	// Initialize the game engine with the UI manager
-	gameEngine := game.NewGameEngine(uiManager)
+	gameEngine := game.NewGameEngineWithContent(uiManager, content)
+	gameEngine.OfflineEfficiency = *offlineEfficiency
+	gameEngine.MaxOfflineDuration = *maxOffline
+	gameEngine.AutosaveInterval = *autosave
+	gameEngine.AutosaveSlots = *autosaveSlots
+
+	// The script runs in the background once the game clock starts; stdin
+	// belongs to the terminal UI, so it can't be read from there
+	if *script != "" {
+		if *script == "-" {
+			fmt.Fprintln(os.Stderr, "Error: --script - is only supported with --headless")
+			os.Exit(1)
+		}
+		startup, err := game.ReadScript(*script)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		startup.StopOnError = *stopOnError
+		gameEngine.StartupScript = startup
+	}
+
+	// Recall commands from previous sessions
+	history, err := game.LoadCommandHistory(game.HistoryPath(), *historySize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	uiManager.SetCommandHistory(history)
+
+	// Offer to resume from the autosave if the last run didn't exit cleanly.
+	// This must be checked before the engine starts and claims the session.
+	if game.PreserveCrashedSession() {
+		uiManager.OfferRecovery()
+	}
 
 	// Set the game engine reference in the UI manager
 	uiManager.SetGameEngine(gameEngine)
@@ -39,11 +138,14 @@ func main() {
 		}
 	}()
 
-	// Handle OS signals
+	// Handle OS signals, saving progress before exiting
 	go func() {
 		<-sigs
-		fmt.Println("\nExiting CivIdleCli...")
 		uiManager.Stop()
+		fmt.Println("\nExiting CivIdleCli...")
+		if err := gameEngine.Shutdown(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving game: %v\n", err)
+		}
 		os.Exit(0)
 	}()
 
@@ -52,4 +154,125 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error running UI: %v\n", err)
 		os.Exit(1)
 	}
+
+	// The UI can also be closed with Ctrl+Q or from the menu, bypassing the
+	// quit command, so make sure the game is saved either way
+	if err := gameEngine.Shutdown(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving game: %v\n", err)
+	}
+}
+
+// loadContent returns the built-in content pack, or the given override pack
+func loadContent(path string) (*game.ContentPack, error) {
+	if path == "" {
+		return game.DefaultContent(), nil
+	}
+	return game.LoadContentPack(path)
+}
+
+// runValidate checks a content pack (or the built-in pack when no file is
+// given) and prints every problem found. It returns the process exit code.
+func runValidate(args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: civcli validate [pack.json]")
+		return 2
+	}
+
+	pack, source := game.DefaultContent(), "built-in"
+	if len(args) == 1 {
+		var err error
+		pack, err = game.ReadContentPack(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		source = args[0]
+	}
+
+	issues := game.ValidateContent(pack, source)
+	if len(issues) == 0 {
+		fmt.Printf("%s: OK, no problems found\n", source)
+		return 0
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue.String())
+	}
+	fmt.Printf("%d problem(s) found\n", len(issues))
+	return 1
+}
+
+// runHeadless simulates the game without the tview UI. Game messages go to
+// stderr and the final state report is written to stdout as JSON.
+func runHeadless(gameEngine *game.GameEngine, ticks int, scriptPath string, stopOnError bool, load string) error {
+	var script *game.Script
+	if scriptPath != "" {
+		var err error
+		script, err = game.ReadScript(scriptPath)
+		if err != nil {
+			return err
+		}
+		script.StopOnError = stopOnError
+	}
+
+	report, err := gameEngine.RunHeadless(game.HeadlessOptions{
+		Ticks:  ticks,
+		Script: script,
+		Load:   load,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// runDiff compares two saves and prints the differences, as JSON with --json.
+// Saves are looked up by name in the save directory unless given as paths.
+// It returns the process exit code.
+func runDiff(args []string) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	saveDir := flags.String("save-dir", "", "directory to look up save names in")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: civcli diff [--json] [--save-dir dir] <saveA> <saveB>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	resolvedSaveDir, err := game.ResolveSaveDir(*saveDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	game.SetSaveDir(resolvedSaveDir)
+
+	diff, err := game.DiffSaveFiles(flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diff); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, line := range diff.Lines() {
+		fmt.Println(line)
+	}
+	return 0
 }
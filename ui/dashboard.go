@@ -2,7 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -10,6 +12,15 @@ import (
 	"github.com/user/civcli/game"
 )
 
+const (
+	// defaultHelpText is the key hint shown below the command input
+	defaultHelpText = " Press [yellow]F1[white] for help • [yellow]Ctrl+Q[white] to quit • [yellow]Tab[white] to complete • [yellow]↑/↓[white] history • [yellow]Ctrl+R[white] search "
+	// commandLabel is the command input's label outside of history search
+	commandLabel = "Command: "
+	// constructionBarWidth is the width of the buildings panel's progress bars
+	constructionBarWidth = 10
+)
+
 // Dashboard provides the main game interface
 type Dashboard struct {
 	ui   *UIManager
@@ -18,14 +29,26 @@ type Dashboard struct {
 	// Layout panels
 	statsPanel     *tview.TextView
 	buildingsPanel *tview.TextView
+	queuePanel     *tview.TextView
 	researchPanel  *tview.TextView
 	logPanel       *tview.TextView
-	commandInput   *tview.InputField
+	commandInput   *promptField
 	helpText       *tview.TextView
 
-	// State tracking
+	// State tracking (guarded by mu: updated from the engine goroutines and
+	// read from the tview event loop)
 	gameState *game.GameState
 	messages  []Message
+	mu        sync.Mutex
+
+	// Command history (only used from the tview event loop)
+	history      *game.CommandHistory
+	historyPos   int    // Entry being recalled; history.Len() while editing a new line
+	historyDraft string // The new line being edited before history was recalled
+	searching    bool   // Reverse history search (Ctrl+R) is active
+	searchQuery  string
+	searchMatch  int    // Index of the current search match, or -1
+	searchDraft  string // Input to restore if the search is cancelled
 }
 
 // Message represents a game message with type and timestamp
@@ -70,7 +93,15 @@ func (d *Dashboard) initializePanels() {
 		SetBorderColor(theme.Border)
 	d.buildingsPanel.SetDynamicColors(true)
 
-	// Research panel - middle right
+	// Build queue panel - middle right
+	d.queuePanel = tview.NewTextView()
+	d.queuePanel.SetBorder(true).
+		SetTitle(" 🏗️ Build Queue ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(theme.Border)
+	d.queuePanel.SetDynamicColors(true)
+
+	// Research panel - bottom right
 	d.researchPanel = tview.NewTextView()
 	d.researchPanel.SetBorder(true).
 		SetTitle(" 🔬 Research & Technology ").
@@ -88,21 +119,23 @@ func (d *Dashboard) initializePanels() {
 		SetScrollable(true)
 
 	// Command input - bottom
-	d.commandInput = tview.NewInputField().
-		SetLabel("Command: ").
+	d.commandInput = newPromptField(theme.Secondary)
+	d.commandInput.
+		SetLabel(commandLabel).
 		SetPlaceholder("Type commands here (e.g., 'build huts', 'research farming') or 'help' for assistance").
 		SetFieldBackgroundColor(theme.Background).
 		SetFieldTextColor(theme.Foreground)
 
 	// Help text - bottom
 	d.helpText = tview.NewTextView().
-		SetText(" Press [yellow]F1[white] for help • [yellow]Ctrl+Q[white] to quit • [yellow]Tab[white] to navigate ").
+		SetText(defaultHelpText).
 		SetTextAlign(tview.AlignCenter).
 		SetDynamicColors(true)
 
 	// Initialize with default content
 	d.updateStatsDisplay()
 	d.updateBuildingsDisplay()
+	d.updateQueueDisplay()
 	d.updateResearchDisplay()
 	d.updateLogDisplay()
 }
@@ -118,11 +151,12 @@ func (d *Dashboard) setupLayout() {
 		AddItem(d.statsPanel, 0, 1, false).
 		AddItem(d.logPanel, 0, 1, false)
 
-	// Right column (buildings and research)
+	// Right column (buildings, build queue and research)
 	rightColumn := tview.NewFlex().SetDirection(tview.FlexRow)
 	rightColumn.
-		AddItem(d.buildingsPanel, 0, 1, false).
-		AddItem(d.researchPanel, 0, 1, false)
+		AddItem(d.buildingsPanel, 0, 2, false).
+		AddItem(d.queuePanel, 0, 1, false).
+		AddItem(d.researchPanel, 0, 2, false)
 
 	// Add columns to main split
 	mainSplit.
@@ -150,28 +184,218 @@ func (d *Dashboard) setupInputHandling() {
 			if command != "" {
 				// Send command to game engine
 				d.ui.SendInput(command)
+				d.mu.Lock()
 				d.addMessage(fmt.Sprintf("> %s", command), "command")
+				d.mu.Unlock()
+				d.recordHistory(command)
 				d.commandInput.SetText("")
 			}
 		}
 	})
 
-	// Set up input capture for navigation
+	// Suggest a completion as the player types
+	d.commandInput.SetChangedFunc(func(text string) {
+		d.helpText.SetText(defaultHelpText)
+		suggestion := ""
+		if text != "" {
+			if candidates := d.completions(text); len(candidates) > 0 {
+				suggestion = candidates[0]
+			}
+		}
+		d.commandInput.SetGhost(suggestion)
+	})
+
 	d.commandInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if d.searching {
+			return d.handleSearchKey(event)
+		}
+
 		switch event.Key() {
 		case tcell.KeyTab:
-			// Tab cycles through focusable elements
-			return event
+			d.completeInput()
+			return nil
+		case tcell.KeyUp:
+			d.recallHistory(-1)
+			return nil
+		case tcell.KeyDown:
+			d.recallHistory(1)
+			return nil
+		case tcell.KeyCtrlR:
+			d.startSearch()
+			return nil
 		}
 		return event
 	})
 }
 
+// SetHistory sets the command history recalled with the arrow keys
+func (d *Dashboard) SetHistory(history *game.CommandHistory) {
+	d.history = history
+	d.historyPos = history.Len()
+}
+
+// recordHistory adds an entered command to the history
+func (d *Dashboard) recordHistory(command string) {
+	if d.history == nil {
+		return
+	}
+	if err := d.history.Add(command); err != nil {
+		d.mu.Lock()
+		d.addMessage(err.Error(), "warning")
+		d.mu.Unlock()
+	}
+	d.historyPos = d.history.Len()
+	d.historyDraft = ""
+}
+
+// recallHistory moves through the history by step entries (-1 is older),
+// returning to the line being edited past the newest entry
+func (d *Dashboard) recallHistory(step int) {
+	if d.history == nil {
+		return
+	}
+	pos := d.historyPos + step
+	if pos < 0 || pos > d.history.Len() {
+		return
+	}
+
+	if d.historyPos == d.history.Len() {
+		d.historyDraft = d.commandInput.GetText()
+	}
+	d.historyPos = pos
+	if pos == d.history.Len() {
+		d.commandInput.SetText(d.historyDraft)
+	} else {
+		d.commandInput.SetText(d.history.Get(pos))
+	}
+}
+
+// startSearch begins a reverse incremental search of the history
+func (d *Dashboard) startSearch() {
+	if d.history == nil {
+		return
+	}
+	d.searching = true
+	d.searchQuery = ""
+	d.searchMatch = -1
+	d.searchDraft = d.commandInput.GetText()
+	d.updateSearch()
+}
+
+// handleSearchKey handles a key press during history search: typing refines
+// the query, Ctrl+R finds an older match, Enter runs the match, Esc cancels
+// and any other key keeps the match for editing
+func (d *Dashboard) handleSearchKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyRune:
+		d.searchQuery += string(event.Rune())
+		d.searchMatch = d.history.Search(d.searchQuery, d.history.Len())
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if query := []rune(d.searchQuery); len(query) > 0 {
+			d.searchQuery = string(query[:len(query)-1])
+		}
+		d.searchMatch = d.history.Search(d.searchQuery, d.history.Len())
+	case tcell.KeyCtrlR:
+		from := d.history.Len()
+		if d.searchMatch >= 0 {
+			from = d.searchMatch
+		}
+		if match := d.history.Search(d.searchQuery, from); match >= 0 {
+			d.searchMatch = match
+		}
+	case tcell.KeyEscape, tcell.KeyCtrlG:
+		d.endSearch()
+		d.commandInput.SetText(d.searchDraft)
+		return nil
+	case tcell.KeyEnter:
+		d.endSearch()
+		return event
+	default:
+		d.endSearch()
+		return event
+	}
+
+	d.updateSearch()
+	return nil
+}
+
+// updateSearch shows the search query in the label and the match as the input
+func (d *Dashboard) updateSearch() {
+	if d.searchQuery != "" && d.searchMatch < 0 {
+		d.commandInput.SetLabel(fmt.Sprintf("(failed reverse-i-search)`%s': ", tview.Escape(d.searchQuery)))
+		return
+	}
+
+	d.commandInput.SetLabel(fmt.Sprintf("(reverse-i-search)`%s': ", tview.Escape(d.searchQuery)))
+	if d.searchMatch >= 0 {
+		d.commandInput.SetText(d.history.Get(d.searchMatch))
+	}
+}
+
+// endSearch leaves history search, keeping the current match in the input
+func (d *Dashboard) endSearch() {
+	d.searching = false
+	d.commandInput.SetLabel(commandLabel)
+	if d.searchMatch >= 0 {
+		d.historyPos = d.searchMatch
+		d.historyDraft = d.searchDraft
+	}
+}
+
+// completions asks the game engine for the completions of a command line
+func (d *Dashboard) completions(text string) []string {
+	engine := d.ui.GetGameEngine()
+	if engine == nil {
+		return nil
+	}
+	return engine.CompleteCommand(text)
+}
+
+// completeInput completes the word being typed: fully if only one
+// completion fits, otherwise as far as all of them agree, listing the
+// choices below the input when that doesn't extend it
+func (d *Dashboard) completeInput() {
+	text := d.commandInput.GetText()
+	candidates := d.completions(text)
+	if len(candidates) == 0 {
+		return
+	}
+
+	completed := commonPrefix(candidates)
+	if len(completed) > len(text) {
+		d.commandInput.SetText(completed)
+		return
+	}
+	if len(candidates) > 1 {
+		words := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			fields := strings.Fields(candidate)
+			words = append(words, fields[len(fields)-1])
+		}
+		d.helpText.SetText(" [yellow]Options:[white] " + tview.Escape(strings.Join(words, "  ")))
+	}
+}
+
+// commonPrefix returns the longest prefix shared by all the strings
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
 // UpdateState updates the dashboard with new game state
 func (d *Dashboard) UpdateState(state game.GameState) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.gameState = &state
 	d.updateStatsDisplay()
 	d.updateBuildingsDisplay()
+	d.updateQueueDisplay()
 	d.updateResearchDisplay()
 	// Remove the direct Draw() call to prevent potential deadlocks
 }
@@ -183,6 +407,9 @@ func (d *Dashboard) updateStatsDisplay() {
 	if d.gameState != nil {
 		state := d.gameState
 
+		if state.CivName != "" {
+			content.WriteString(fmt.Sprintf("[yellow]🏛️ Civilization:[white] %s\n", state.CivName))
+		}
 		content.WriteString(fmt.Sprintf("[yellow]📅 Age:[white] %s\n", state.Age))
 		content.WriteString(fmt.Sprintf("[yellow]⏰ Tick:[white] %d\n", state.Tick))
 		content.WriteString(fmt.Sprintf("[yellow]👥 Villagers:[white] %d/%d\n", len(state.Villagers), state.VillagerCap))
@@ -191,11 +418,16 @@ func (d *Dashboard) updateStatsDisplay() {
 		// Display resources from the map
 		for resource, amount := range state.Resources {
 			emoji := d.getResourceEmoji(resource)
-			content.WriteString(fmt.Sprintf("  %s %s: %.1f\n", emoji, resource, amount))
+			content.WriteString(fmt.Sprintf("  %s %s: %.1f (%+.1f/tick)\n", emoji, resource, amount, state.Rates[resource]))
 		}
 
-		content.WriteString(fmt.Sprintf("\n[green]Total Food:[white] %.1f\n", state.TotalFood))
+		content.WriteString(fmt.Sprintf("\n[green]Total Food:[white] %.1f (%+.1f/tick)\n", state.TotalFood, state.FoodRate))
 		content.WriteString(fmt.Sprintf("[yellow]Tick Duration:[white] %.1fs\n", state.TickDurationSeconds))
+		if state.Paused {
+			content.WriteString(fmt.Sprintf("[yellow]Speed:[white] %gx [red](paused)[white]\n", state.Speed))
+		} else {
+			content.WriteString(fmt.Sprintf("[yellow]Speed:[white] %gx\n", state.Speed))
+		}
 	} else {
 		content.WriteString("[yellow]Welcome to CivIdleCli![white]\n\n")
 		content.WriteString("🌟 Starting a new civilization...\n")
@@ -218,6 +450,17 @@ func (d *Dashboard) updateBuildingsDisplay() {
 		for building, count := range d.gameState.Buildings {
 			content.WriteString(fmt.Sprintf("🏠 %s: %d\n", building, count))
 		}
+
+		if len(d.gameState.Construction) > 0 {
+			content.WriteString(fmt.Sprintf("\n[cyan]Under Construction[white] (%d builders):\n", d.gameState.Builders))
+			for _, site := range d.gameState.Construction {
+				content.WriteString(fmt.Sprintf("🚧 %s %s %.0f%% [gray]#%d[white]\n",
+					site.Label(), progressBar(site.Progress(), constructionBarWidth), site.Progress()*100, site.ID))
+			}
+			if d.gameState.Builders == 0 {
+				content.WriteString("[red]No builders assigned[white]\n")
+			}
+		}
 	} else {
 		content.WriteString("[yellow]Available Buildings:[white]\n\n")
 		content.WriteString("🏠 [green]huts[white] - Increase population capacity\n")
@@ -234,6 +477,50 @@ func (d *Dashboard) updateBuildingsDisplay() {
 	d.buildingsPanel.SetText(content.String())
 }
 
+// progressBar renders a fraction from 0 to 1 as a bar of the given width
+func progressBar(fraction float64, width int) string {
+	filled := int(fraction*float64(width) + 0.5)
+	filled = min(max(filled, 0), width)
+	return "[green]" + strings.Repeat("█", filled) + "[gray]" + strings.Repeat("░", width-filled) + "[white]"
+}
+
+// updateQueueDisplay refreshes the build queue panel, showing each order's
+// progress and the cost of its next building, with shortfalls in red
+func (d *Dashboard) updateQueueDisplay() {
+	var content strings.Builder
+
+	if d.gameState == nil || len(d.gameState.BuildQueue) == 0 {
+		content.WriteString("[gray]Nothing queued[white]\n")
+		content.WriteString("\n[cyan]Use:[white] 'queue build <building> [count[]'")
+		d.queuePanel.SetText(content.String())
+		return
+	}
+
+	for i, order := range d.gameState.BuildQueue {
+		content.WriteString(fmt.Sprintf("%d. [green]%s[white] %d/%d [gray]#%d[white]", i+1, order.Building, order.Built, order.Count, order.ID))
+		resources := make([]string, 0, len(order.Cost))
+		for resource := range order.Cost {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+
+		costs := []string{}
+		for _, resource := range resources {
+			color := "white"
+			if d.gameState.Resources[resource] < order.Cost[resource] {
+				color = "red"
+			}
+			costs = append(costs, fmt.Sprintf("[%s]%.0f %s[white]", color, order.Cost[resource], resource))
+		}
+		if len(costs) > 0 {
+			content.WriteString(" - " + strings.Join(costs, ", "))
+		}
+		content.WriteString("\n")
+	}
+
+	d.queuePanel.SetText(content.String())
+}
+
 // updateResearchDisplay refreshes the research panel
 func (d *Dashboard) updateResearchDisplay() {
 	var content strings.Builder
@@ -253,6 +540,14 @@ func (d *Dashboard) updateResearchDisplay() {
 			content.WriteString(fmt.Sprintf("[cyan]Progress:[white] %.1f%%\n\n", progress))
 		}
 
+		if len(d.gameState.Research.Queue) > 0 {
+			content.WriteString("[yellow]Research Queue:[white]\n")
+			for i, tech := range d.gameState.Research.Queue {
+				content.WriteString(fmt.Sprintf("  %d. %s\n", i+1, tech))
+			}
+			content.WriteString("\n")
+		}
+
 		content.WriteString("[yellow]Available Research:[white]\n")
 		content.WriteString("🔬 [green]Agriculture[white] - Unlock advanced farming\n")
 		content.WriteString("⚒️ [green]Tool Making[white] - Create better tools\n")
@@ -334,12 +629,15 @@ func (d *Dashboard) getMessageColor(msgType string) string {
 
 // ShowMessage adds a message to the log
 func (d *Dashboard) ShowMessage(message, msgType string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.addMessage(message, msgType)
 	d.updateLogDisplay()
 	// Remove Draw() call to prevent potential conflicts during page transitions
 }
 
-// addMessage adds a message to the internal log
+// addMessage adds a message to the internal log; the caller must hold mu
 func (d *Dashboard) addMessage(message, msgType string) {
 	msg := Message{
 		Text:      message,
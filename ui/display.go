@@ -187,7 +187,7 @@ func (d *Display) DisplayDashboard(state game.GameState) {
 	resourcesText := ""
 	if len(state.Resources) > 0 {
 		for name, amount := range state.Resources {
-			resourcesText += fmt.Sprintf("%s: %.1f\n", name, amount)
+			resourcesText += fmt.Sprintf("%s: %.1f (%+.1f/tick)\n", name, amount, state.Rates[name])
 		}
 	} else {
 		resourcesText = "No resources available"
@@ -219,8 +219,11 @@ func (d *Display) DisplayDashboard(state game.GameState) {
 	d.SetBuildings(buildingsText)
 
 	// Format status
-	statusText := fmt.Sprintf("Age: %s\nTick: %d\nVillagers: %d/%d\nTick Duration: %.1f sec",
-		state.Age, state.Tick, totalVillagers, state.VillagerCap, state.TickDurationSeconds)
+	statusText := fmt.Sprintf("Age: %s\nTick: %d\nVillagers: %d/%d\nTick Duration: %.1f sec\nSpeed: %gx",
+		state.Age, state.Tick, totalVillagers, state.VillagerCap, state.TickDurationSeconds, state.Speed)
+	if state.Paused {
+		statusText += " (paused)"
+	}
 	d.SetStatus(statusText)
 
 	// Format research
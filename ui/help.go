@@ -149,8 +149,11 @@ Welcome to CivIdleCli, a command-line civilization building game! Here's everyth
 
 [cyan::b]⌨️  Basic Commands[white::-]
 
-• [yellow]build <building>[white] - Construct a building
-• [yellow]research <technology>[white] - Research a new technology
+• [yellow]build <building>[white] - Construct a building (assign villagers to [green]construction[white] to build it)
+• [yellow]upgrade <building>[white] - Upgrade a building to its next tier, e.g. a hut to a house
+• [yellow]construction[white] - Show buildings under construction ([green]construction cancel <site>[white] for a partial refund)
+• [yellow]research <technology>[white] - Research a new technology, or queue it
+• [yellow]research cancel <technology>[white] - Remove a technology from the research queue
 • [yellow]status[white] - Show detailed civilization status
 • [yellow]help[white] - Show this help system
 • [yellow]save[white] - Save your current game
@@ -195,7 +198,17 @@ Here are all the commands available in CivIdleCli:
 [cyan::b]💾 Game Management[white::-]
 
 • [green]save[white] - Save your current game progress
+• [green]name <civilization name>[white] - Name your civilization
 • [green]load[white] - Load a previously saved game
+• [green]load <name> unverified[white] - Load a save that failed its integrity check
+• [green]export <save>[white] - Share a save as a compressed string and .civsave file
+• [green]import <string|file> <name>[white] - Import a shared save
+• [green]pause[white] / [green]resume[white] - Stop and restart the game clock
+• [green]speed <x>[white] - Change the game speed (e.g. 0.5x, 2x, 10x)
+• [green]queue build <building> [count[][white] - Build once affordable ([green]queue list/cancel/reorder[white])
+• [green]run <file> [stop-on-error][white] - Run a script of commands ([green]run stop[white] stops it)
+• [green]rule add when <condition> <action>[white] - Automate, e.g. rule add when wood >= 20 build hut
+• [green]rule list[white] / [green]remove <n>[white] / [green]enable <n>[white] / [green]disable <n>[white] - Manage rules
 • [green]help[white] - Open this help system
 • [green]quit[white] - Exit the game
 
@@ -203,7 +216,9 @@ Here are all the commands available in CivIdleCli:
 
 • [yellow]F1[white] - Quick help
 • [yellow]Ctrl+Q[white] - Quick quit
-• [yellow]Tab[white] - Navigate interface elements
+• [yellow]Tab[white] - Complete commands, buildings, technologies and save names
+• [yellow]↑/↓[white] - Recall earlier commands
+• [yellow]Ctrl+R[white] - Search the command history
 • [yellow]ESC[white] - Return to previous screen
 
 [green::b]💡 Command Tips:[white::-]
@@ -340,7 +355,7 @@ Research is how your civilization advances through the ages. Each technology unl
 [cyan::b]🎓 How Research Works[white::-]
 
 1. [yellow]Generate Research Points:[white] Villagers automatically generate research
-2. [yellow]Choose Technology:[white] Use 'research <technology>' command
+2. [yellow]Choose Technology:[white] Use 'research <technology>' command; it is queued if something is already being researched
 3. [yellow]Wait for Completion:[white] Research progresses automatically over time
 4. [yellow]Enjoy Benefits:[white] New capabilities unlock immediately
 
@@ -352,7 +367,7 @@ Research is how your civilization advances through the ages. Each technology unl
 
 [green::b]💡 Research Tips:[white::-]
 • Research continues even when you're not playing
-• Some technologies are prerequisites for others
+• Some technologies are prerequisites for others; missing ones are queued for you
 • Plan your research path based on your civilization's needs`
 
 	h.content.SetText(content)
@@ -531,14 +546,14 @@ Understanding how CivIdleCli works under the hood will help you make better stra
 [cyan::b]🕐 The Tick System[white::-]
 
 [green]What is a Tick?[white]
-• A tick is one game cycle (default: 2 seconds)
+• A tick is one game cycle (default: 5 seconds)
 • All automatic actions happen on each tick
 • Resources are generated, research progresses, population grows
 
 [green]Tick Duration:[white]
-• Default: 2 seconds per tick
+• Default: 5 seconds per tick at 1x speed
 • Displayed in the stats panel
-• Affects the pace of the game
+• Change it with 'speed <x>' or stop the clock with 'pause'
 
 [cyan::b]📈 Automatic Processes[white::-]
 
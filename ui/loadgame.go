@@ -11,6 +11,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/user/civcli/game"
 )
 
 // LoadGame provides the save game loading interface
@@ -20,9 +21,34 @@ type LoadGame struct {
 	saveList     *tview.List
 	infoPanel    *tview.TextView
 	actionPanel  *tview.TextView
+	filterInput  *tview.InputField
 	returnPage   string
-	saveFiles    []SaveFileInfo
+	allSaves     []*SaveFileInfo // Every save found in the save directory
+	saveFiles    []*SaveFileInfo // The saves shown, after filtering and sorting
 	selectedSave *SaveFileInfo
+	sortMode     saveSortMode
+	filterText   string
+}
+
+// saveSortMode selects the order of the save list
+type saveSortMode int
+
+const (
+	sortByDate saveSortMode = iota // Newest first
+	sortByName                     // Alphabetical
+	sortByAge                      // Most advanced age first
+	saveSortModes
+)
+
+// String names the sort mode for display
+func (m saveSortMode) String() string {
+	switch m {
+	case sortByName:
+		return "name"
+	case sortByAge:
+		return "age"
+	}
+	return "date"
 }
 
 // SaveFileInfo represents information about a save file
@@ -33,6 +59,9 @@ type SaveFileInfo struct {
 	ModTime      time.Time
 	IsValid      bool
 	ErrorMessage string
+	Checked      bool               // Whether the whole save has been validated, not just its header
+	Integrity    game.SaveIntegrity // Result of the checksum check, set for checked valid saves
+	Header       *game.SaveHeader   // Summary of the game, set for valid saves
 }
 
 // NewLoadGame creates a new load game screen
@@ -43,10 +72,12 @@ func NewLoadGame(ui *UIManager) *LoadGame {
 		saveList:    tview.NewList(),
 		infoPanel:   tview.NewTextView(),
 		actionPanel: tview.NewTextView(),
+		filterInput: tview.NewInputField(),
 		returnPage:  "splash",
-		saveFiles:   make([]SaveFileInfo, 0),
+		saveFiles:   make([]*SaveFileInfo, 0),
 	}
 
+	lg.setupFilterInput()
 	lg.setupSaveList()
 	lg.setupInfoPanel()
 	lg.setupActionPanel()
@@ -77,7 +108,8 @@ func (lg *LoadGame) setupSaveList() {
 
 		// Validate index bounds
 		if index >= 0 && index < len(lg.saveFiles) {
-			lg.selectedSave = &lg.saveFiles[index]
+			lg.selectedSave = lg.saveFiles[index]
+			lg.checkSave(index)
 			lg.updateInfoPanel()
 			lg.updateActionPanel()
 		} else {
@@ -120,11 +152,45 @@ func (lg *LoadGame) setupSaveList() {
 				lg.ui.ShowMessage("No save file selected for deletion", "warning")
 			}
 			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 's':
+				lg.sortMode = (lg.sortMode + 1) % saveSortModes
+				lg.applyView()
+				return nil
+			case '/':
+				lg.ui.GetApp().SetFocus(lg.filterInput)
+				return nil
+			}
 		}
 		return event
 	})
 }
 
+// setupFilterInput creates the filter box above the save list
+func (lg *LoadGame) setupFilterInput() {
+	theme := lg.ui.GetTheme()
+
+	lg.filterInput.SetLabel("🔍 Filter: ").
+		SetPlaceholder("name, civilization, age or date (press /)").
+		SetFieldBackgroundColor(theme.Background).
+		SetBorder(true).
+		SetBorderColor(theme.Border)
+
+	lg.filterInput.SetChangedFunc(func(text string) {
+		lg.filterText = text
+		lg.applyView()
+	})
+
+	// Enter or Escape returns to the list; Escape also clears the filter
+	lg.filterInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			lg.filterInput.SetText("")
+		}
+		lg.ui.GetApp().SetFocus(lg.saveList)
+	})
+}
+
 // setupInfoPanel creates the save file information display
 func (lg *LoadGame) setupInfoPanel() {
 	theme := lg.ui.GetTheme()
@@ -154,6 +220,8 @@ func (lg *LoadGame) setupActionPanel() {
 [yellow]Enter[white] - Load selected save game
 [yellow]Delete[white] - Delete selected save file
 [yellow]Ctrl+R[white] - Refresh save file list
+[yellow]s[white] - Sort by date, name or age
+[yellow]/[white] - Filter saves
 [yellow]ESC[white] - Return to main menu
 
 [green]Navigation:[white]
@@ -167,13 +235,15 @@ Tab - Switch between panels`
 func (lg *LoadGame) setupLayout() {
 	// Create left panel (save list)
 	leftPanel := tview.NewFlex().SetDirection(tview.FlexRow)
-	leftPanel.AddItem(lg.saveList, 0, 1, true)
+	leftPanel.
+		AddItem(lg.filterInput, 3, 0, false).
+		AddItem(lg.saveList, 0, 1, true)
 
 	// Create right panel (info and actions)
 	rightPanel := tview.NewFlex().SetDirection(tview.FlexRow)
 	rightPanel.
 		AddItem(lg.infoPanel, 0, 2, false).
-		AddItem(lg.actionPanel, 12, 0, false)
+		AddItem(lg.actionPanel, 14, 0, false)
 
 	// Main layout
 	lg.view.SetDirection(tview.FlexColumn)
@@ -184,15 +254,16 @@ func (lg *LoadGame) setupLayout() {
 
 // refreshSaveFiles scans for save files and updates the list
 func (lg *LoadGame) refreshSaveFiles() {
-	lg.saveFiles = make([]SaveFileInfo, 0)
+	lg.allSaves = make([]*SaveFileInfo, 0)
+	lg.saveFiles = make([]*SaveFileInfo, 0)
 	lg.selectedSave = nil
 
-	saveDir := "./data/saves"
+	saveDir := game.SaveDir()
 
 	// Check if save directory exists
 	if _, err := os.Stat(saveDir); os.IsNotExist(err) {
 		lg.saveList.Clear()
-		lg.saveList.AddItem("📁 No save directory found", "Create a save directory: ./data/saves", 0, nil)
+		lg.saveList.AddItem("📁 No save directory found", "Saves will be created in "+saveDir, 0, nil)
 		lg.updateInfoPanel()
 		lg.updateActionPanel()
 		return
@@ -224,7 +295,7 @@ func (lg *LoadGame) refreshSaveFiles() {
 			continue
 		}
 
-		saveInfo := SaveFileInfo{
+		saveInfo := &SaveFileInfo{
 			Name:    file.Name(),
 			Path:    fullPath,
 			Size:    info.Size(),
@@ -232,25 +303,98 @@ func (lg *LoadGame) refreshSaveFiles() {
 			IsValid: true,
 		}
 
-		// Validate the save file
-		isValid, errorMsg := lg.validateSaveFile(fullPath)
-		saveInfo.IsValid = isValid
-		saveInfo.ErrorMessage = errorMsg
+		// Only the header is read here; the rest of a save is validated
+		// once it is selected
+		if info.Size() == 0 {
+			saveInfo.IsValid = false
+			saveInfo.ErrorMessage = "File is empty"
+		} else if header, err := game.ReadSaveHeader(fullPath); err != nil {
+			saveInfo.IsValid = false
+			saveInfo.ErrorMessage = err.Error()
+		} else {
+			saveInfo.Header = header
+		}
+		saveInfo.Checked = !saveInfo.IsValid
+
+		lg.allSaves = append(lg.allSaves, saveInfo)
+	}
+
+	lg.applyView()
+}
 
-		lg.saveFiles = append(lg.saveFiles, saveInfo)
+// applyView filters and sorts the saves found by refreshSaveFiles and
+// updates the display
+func (lg *LoadGame) applyView() {
+	lg.selectedSave = nil
+	lg.saveFiles = make([]*SaveFileInfo, 0, len(lg.allSaves))
+	filter := strings.ToLower(strings.TrimSpace(lg.filterText))
+	for _, save := range lg.allSaves {
+		if filter == "" || lg.matchesFilter(save, filter) {
+			lg.saveFiles = append(lg.saveFiles, save)
+		}
 	}
 
-	// Sort by modification time (newest first)
-	sort.Slice(lg.saveFiles, func(i, j int) bool {
-		return lg.saveFiles[i].ModTime.After(lg.saveFiles[j].ModTime)
+	ageOrder := lg.ageOrder()
+	sort.SliceStable(lg.saveFiles, func(i, j int) bool {
+		a, b := lg.saveFiles[i], lg.saveFiles[j]
+		switch lg.sortMode {
+		case sortByName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case sortByAge:
+			if ageA, ageB := lg.ageRank(a, ageOrder), lg.ageRank(b, ageOrder); ageA != ageB {
+				return ageA > ageB
+			}
+			if a.Header != nil && b.Header != nil && a.Header.Tick != b.Header.Tick {
+				return a.Header.Tick > b.Header.Tick
+			}
+		}
+		return a.ModTime.After(b.ModTime)
 	})
 
+	lg.saveList.SetTitle(fmt.Sprintf(" 💾 Saved Games (%d, by %s) ", len(lg.saveFiles), lg.sortMode))
+
 	// Update the list
 	lg.updateSaveList()
 	lg.updateInfoPanel()
 	lg.updateActionPanel()
 }
 
+// matchesFilter reports whether a save's name, civilization, age or date contains the filter text
+func (lg *LoadGame) matchesFilter(save *SaveFileInfo, filter string) bool {
+	fields := []string{save.Name, save.ModTime.Format("2006-01-02")}
+	if save.Header != nil {
+		fields = append(fields, save.Header.CivName, save.Header.Age)
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// ageOrder maps age names to their position in the game's progression
+func (lg *LoadGame) ageOrder() map[string]int {
+	order := make(map[string]int)
+	if gameEngine := lg.ui.GetGameEngine(); gameEngine != nil {
+		for i, age := range gameEngine.Content.AgeNames() {
+			order[age] = i
+		}
+	}
+	return order
+}
+
+// ageRank returns how far a save has progressed through the ages, or -1 if unknown
+func (lg *LoadGame) ageRank(save *SaveFileInfo, ageOrder map[string]int) int {
+	if save.Header == nil {
+		return -1
+	}
+	if rank, exists := ageOrder[save.Header.Age]; exists {
+		return rank
+	}
+	return -1
+}
+
 // updateSaveList refreshes the save file list display
 func (lg *LoadGame) updateSaveList() {
 	lg.saveList.Clear()
@@ -261,25 +405,66 @@ func (lg *LoadGame) updateSaveList() {
 	}
 
 	for i, save := range lg.saveFiles {
-		mainText := fmt.Sprintf("💾 %s", save.Name)
-		secondaryText := fmt.Sprintf("Modified: %s | Size: %s",
-			save.ModTime.Format("2006-01-02 15:04"),
-			lg.formatFileSize(save.Size))
-
-		if !save.IsValid {
-			mainText = fmt.Sprintf("❌ %s (Invalid)", save.Name)
-			secondaryText = save.ErrorMessage
+		mainText, secondaryText := lg.saveListText(save)
+		lg.saveList.AddItem(mainText, secondaryText, rune('1'+i), nil)
+	}
+}
+
+// saveListText returns the main and secondary text of a save's list entry
+func (lg *LoadGame) saveListText(save *SaveFileInfo) (string, string) {
+	if !save.IsValid {
+		return fmt.Sprintf("❌ %s (Invalid)", save.Name), save.ErrorMessage
+	}
+	if save.Integrity == game.IntegrityTampered {
+		return fmt.Sprintf("⚠️  %s (Modified)", save.Name), "Checksum mismatch - can only be loaded unverified"
+	}
+
+	mainText := fmt.Sprintf("💾 %s", save.Name)
+	secondaryText := fmt.Sprintf("Modified: %s | Size: %s",
+		save.ModTime.Format("2006-01-02 15:04"),
+		lg.formatFileSize(save.Size))
+	if save.Header != nil {
+		if save.Header.CivName != "" {
+			mainText = fmt.Sprintf("💾 %s - %s", save.Name, save.Header.CivName)
 		}
+		secondaryText = fmt.Sprintf("%s, tick %d | %s",
+			save.Header.Age, save.Header.Tick, save.ModTime.Format("2006-01-02 15:04"))
+	}
+	return mainText, secondaryText
+}
 
-		lg.saveList.AddItem(mainText, secondaryText, rune('1'+i), nil)
+// checkSave validates the whole of a listed save and verifies its integrity,
+// the first time it is selected, and updates its list entry with the result
+func (lg *LoadGame) checkSave(index int) {
+	save := lg.saveFiles[index]
+	if save.Checked {
+		return
+	}
+	save.Checked = true
+
+	data, err := os.ReadFile(save.Path)
+	if err != nil {
+		save.IsValid = false
+		save.ErrorMessage = "Cannot read file"
+	} else if valid, errorMsg := lg.validateSaveData(data); !valid {
+		save.IsValid = false
+		save.ErrorMessage = errorMsg
+	} else if integrity, err := game.VerifySave(data); err != nil {
+		save.IsValid = false
+		save.ErrorMessage = err.Error()
+	} else {
+		save.Integrity = integrity
 	}
+
+	mainText, secondaryText := lg.saveListText(save)
+	lg.saveList.SetItemText(index, mainText, secondaryText)
 }
 
 // updateInfoPanel refreshes the save file information display
 func (lg *LoadGame) updateInfoPanel() {
 	if lg.selectedSave == nil {
 		if len(lg.saveFiles) == 0 {
-			content := `[yellow::b]📂 No Save Files Found[white::-]
+			content := fmt.Sprintf(`[yellow::b]📂 No Save Files Found[white::-]
 
 No saved games were found in the save directory.
 
@@ -289,9 +474,9 @@ No saved games were found in the save directory.
 3. The game will auto-save your progress
 
 [cyan]Save Location:[white]
-./data/saves/
+%s
 
-[green]Create your first civilization to get started![white]`
+[green]Create your first civilization to get started![white]`, game.SaveDir())
 			lg.infoPanel.SetText(content)
 		} else {
 			lg.infoPanel.SetText("[yellow]Select a save file to view details[white]")
@@ -312,9 +497,7 @@ No saved games were found in the save directory.
 [cyan]Status:[white] %s
 
 [yellow]Game Information:[white]
-• Format: JSON save file
-• Compatible: Yes
-• Backup: Available
+%s
 
 [green]Press Enter to load this save game[white]`,
 		save.Name,
@@ -322,11 +505,41 @@ No saved games were found in the save directory.
 		lg.formatFileSize(save.Size),
 		save.ModTime.Format("Monday, January 2, 2006"),
 		save.ModTime.Format("15:04:05"),
-		lg.getStatusText(save))
+		lg.getStatusText(save),
+		lg.formatGameInfo(save.Header))
 
 	lg.infoPanel.SetText(content)
 }
 
+// formatGameInfo describes the civilization summarized in a save header
+func (lg *LoadGame) formatGameInfo(header *game.SaveHeader) string {
+	if header == nil {
+		return "• No details available"
+	}
+
+	civName := header.CivName
+	if civName == "" {
+		civName = "Unnamed"
+	}
+
+	lines := []string{
+		fmt.Sprintf("• Civilization: %s", civName),
+		fmt.Sprintf("• Age: %s", header.Age),
+		fmt.Sprintf("• Tick: %d", header.Tick),
+		fmt.Sprintf("• Population: %d", header.Population),
+		fmt.Sprintf("• Technologies: %d researched", header.Researched),
+	}
+	if header.Researching != "" {
+		lines = append(lines, fmt.Sprintf("• Researching: %s", header.Researching))
+	}
+	if header.PlayTime != "" {
+		lines = append(lines, fmt.Sprintf("• Play time: %s", header.PlayTime))
+	}
+	lines = append(lines, fmt.Sprintf("• Stockpiles: %s", header.Thumbnail))
+
+	return strings.Join(lines, "\n")
+}
+
 // updateActionPanel refreshes the action panel
 func (lg *LoadGame) updateActionPanel() {
 	if lg.selectedSave == nil {
@@ -358,25 +571,8 @@ Tab - Switch between panels`
 	lg.actionPanel.SetText(actionText)
 }
 
-// validateSaveFile performs basic validation on a save file
-func (lg *LoadGame) validateSaveFile(path string) (bool, string) {
-	// Check if file exists
-	info, err := os.Stat(path)
-	if err != nil {
-		return false, "File not found"
-	}
-
-	// Check if file is empty
-	if info.Size() == 0 {
-		return false, "File is empty"
-	}
-
-	// Read file content
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return false, "Cannot read file"
-	}
-
+// validateSaveData performs basic validation on the contents of a save file
+func (lg *LoadGame) validateSaveData(data []byte) (bool, string) {
 	// Basic JSON validation
 	trimmed := strings.TrimSpace(string(data))
 	if !strings.HasPrefix(trimmed, "{") {
@@ -414,7 +610,15 @@ func (lg *LoadGame) getStatusText(save *SaveFileInfo) string {
 	if !save.IsValid {
 		return fmt.Sprintf("❌ Invalid (%s)", save.ErrorMessage)
 	}
-	return "✅ Valid save file"
+	switch save.Integrity {
+	case game.IntegrityTampered:
+		return "⚠️  Modified or corrupted (checksum mismatch)"
+	case game.IntegrityLegacy:
+		return "✅ Valid save file (no checksum, older version)"
+	case game.IntegrityUnsigned:
+		return "✅ Valid save file (checksum verified, not yet signed with your save key)"
+	}
+	return "✅ Valid save file (checksum verified)"
 }
 
 // showLoadConfirmation shows a proper confirmation dialog with warning about overwriting current game
@@ -424,14 +628,23 @@ func (lg *LoadGame) showLoadConfirmation(index int) {
 		return
 	}
 
+	lg.checkSave(index)
 	selectedSave := lg.saveFiles[index]
 	fileName := strings.TrimSuffix(selectedSave.Name, ".json")
+	unverified := selectedSave.Integrity == game.IntegrityTampered
+
+	warning := ""
+	buttons := []string{"YES - Load Game", "NO - Cancel"}
+	if unverified {
+		warning = "\n[red::b]This save failed its integrity check. It may be corrupted or hand-edited,\nand loading it will be recorded in your statistics.[white::-]\n"
+		buttons = []string{"Load Unverified", "NO - Cancel"}
+	}
 
 	// Create a detailed confirmation modal
 	confirmText := fmt.Sprintf(`[yellow::b]Load Save Game: %s[white::-]
 
 [red::b]⚠️  WARNING: This will overwrite any current game progress![white::-]
-
+%s
 [cyan]Game Details:[white]
 • File: %s
 • Size: %s
@@ -440,21 +653,22 @@ func (lg *LoadGame) showLoadConfirmation(index int) {
 
 [green]Do you want to continue loading this save game?[white]`,
 		fileName,
+		warning,
 		selectedSave.Name,
 		lg.formatFileSize(selectedSave.Size),
 		selectedSave.ModTime.Format("2006-01-02 15:04"),
-		lg.getStatusText(&selectedSave))
+		lg.getStatusText(selectedSave))
 
 	modal := tview.NewModal().
 		SetText(confirmText).
-		AddButtons([]string{"YES - Load Game", "NO - Cancel"}).
+		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			// Remove the modal first
 			lg.ui.GetPages().RemovePage("loadConfirm")
 
 			if buttonIndex == 0 { // YES - Load Game
 				// Load directly without goroutine to avoid race conditions
-				lg.performGameLoad(fileName)
+				lg.performGameLoad(fileName, unverified)
 			}
 			// If NO - Cancel, just return to the load game screen (do nothing)
 		})
@@ -465,14 +679,19 @@ func (lg *LoadGame) showLoadConfirmation(index int) {
 }
 
 // performGameLoad performs the complete game loading process - minimal approach
-func (lg *LoadGame) performGameLoad(fileName string) {
+func (lg *LoadGame) performGameLoad(fileName string, unverified bool) {
 	gameEngine := lg.ui.GetGameEngine()
 	if gameEngine == nil {
 		return // Fail silently to avoid UI conflicts
 	}
 
 	// Load the game exactly like the in-game command does
-	err := gameEngine.LoadGame(fileName)
+	var err error
+	if unverified {
+		err = gameEngine.LoadGameUnverified(fileName)
+	} else {
+		err = gameEngine.LoadGame(fileName)
+	}
 
 	if err != nil {
 		return // Fail silently to avoid UI conflicts for now
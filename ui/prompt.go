@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// promptField is an input field that shows the rest of a suggested
+// completion as dimmed "ghost" text after what has been typed
+type promptField struct {
+	*tview.InputField
+	ghost      string // Suggested full text; only shown while it extends the input
+	ghostColor tcell.Color
+}
+
+// newPromptField creates a prompt field with no suggestion
+func newPromptField(ghostColor tcell.Color) *promptField {
+	return &promptField{
+		InputField: tview.NewInputField(),
+		ghostColor: ghostColor,
+	}
+}
+
+// SetGhost sets the suggested completion shown after the typed text
+func (p *promptField) SetGhost(suggestion string) {
+	p.ghost = suggestion
+}
+
+// Draw draws the input field and then the ghost text, if it fits
+func (p *promptField) Draw(screen tcell.Screen) {
+	p.InputField.Draw(screen)
+
+	text := p.GetText()
+	if !p.HasFocus() || text == "" || len(p.ghost) <= len(text) || !strings.HasPrefix(p.ghost, text) {
+		return
+	}
+
+	x, y, width, height := p.GetInnerRect()
+	if height < 1 {
+		return
+	}
+	labelWidth := tview.TaggedStringWidth(p.GetLabel())
+	start := x + labelWidth + tview.TaggedStringWidth(tview.Escape(text))
+	// Leave the last cell free for the cursor once the text reaches the edge
+	available := x + width - 1 - start
+	if available <= 0 {
+		return
+	}
+
+	tview.Print(screen, tview.Escape(p.ghost[len(text):]), start, y, available, tview.AlignLeft, p.ghostColor)
+}
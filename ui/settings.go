@@ -8,6 +8,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/user/civcli/game"
 )
 
 // Settings provides the game settings interface
@@ -67,7 +68,7 @@ func (s *Settings) setupMenu() {
 	})
 
 	s.menu.AddItem("📁 Open Save Directory", "View save game location", 's', func() {
-		s.ui.ShowMessage("Save directory: ./data/saves/", "info")
+		s.ui.ShowMessage("Save directory: "+game.SaveDir(), "info")
 	})
 
 	s.menu.AddItem("🔙 Back", "Return to previous screen", 'b', func() {
@@ -109,7 +110,7 @@ func (s *Settings) generateInfoContent() string {
 	}
 
 	// Get save directory info
-	saveDir := filepath.Join(cwd, "data", "saves")
+	saveDir := game.SaveDir()
 	saveDirExists := "❌ Not found"
 	if _, err := os.Stat(saveDir); err == nil {
 		saveDirExists = "✅ Exists"
@@ -155,8 +156,8 @@ func (s *Settings) generateInfoContent() string {
 [cyan::b]💾 Save Game Information[white::-]
 • Save Format: JSON
 • Auto-save: Enabled
-• Save Location: ./data/saves/
-• Backup System: Not implemented
+• Save Location: %s
+• Backup System: Rotating autosaves
 
 [cyan::b]🔧 System Information[white::-]
 • Terminal Support: ✅ Full color
@@ -184,7 +185,8 @@ Ready to build your civilization!`,
 		execPath,
 		saveDir,
 		saveDirExists,
-		saveCount)
+		saveCount,
+		saveDir)
 
 	return content
 }
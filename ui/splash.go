@@ -2,6 +2,7 @@ package ui
 
 import (
 	"github.com/rivo/tview"
+	"github.com/user/civcli/game"
 )
 
 // SplashScreen provides a modern welcome screen
@@ -99,6 +100,23 @@ func (s *SplashScreen) setupMenu() {
 	})
 }
 
+// OfferRecovery adds a menu option to resume from the autosave kept by
+// game.PreserveCrashedSession, for when the previous session ended without
+// a clean shutdown
+func (s *SplashScreen) OfferRecovery() {
+	s.menu.InsertItem(0, "🩹 Recover Last Session", "The last session ended unexpectedly; resume from its autosave", 'r', func() {
+		gameEngine := s.ui.GetGameEngine()
+		if gameEngine == nil {
+			return
+		}
+		if err := gameEngine.LoadGame(game.RecoverySave); err != nil {
+			s.ui.ShowMessage("Failed to recover last session: "+err.Error(), "error")
+		}
+		s.ui.ShowDashboard()
+	})
+	s.menu.SetCurrentItem(0)
+}
+
 // setupLayout arranges the splash screen components
 func (s *SplashScreen) setupLayout() {
 	// Create vertical layout
@@ -109,7 +127,7 @@ func (s *SplashScreen) setupLayout() {
 		AddItem(nil, 2, 0, false).    // Top padding
 		AddItem(s.logo, 0, 1, false). // Logo and description
 		AddItem(nil, 1, 0, false).    // Spacing
-		AddItem(s.menu, 12, 0, true). // Menu
+		AddItem(s.menu, 14, 0, true). // Menu
 		AddItem(nil, 2, 0, false)     // Bottom padding
 
 	// Create horizontal centering
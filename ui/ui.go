@@ -166,6 +166,11 @@ func (ui *UIManager) ShowSplash() {
 	ui.pages.SwitchToPage("splash")
 }
 
+// OfferRecovery shows a "recover last session" option on the splash screen
+func (ui *UIManager) OfferRecovery() {
+	ui.splash.OfferRecovery()
+}
+
 // ShowDashboard displays the main game dashboard
 func (ui *UIManager) ShowDashboard() {
 	ui.mu.Lock()
@@ -313,6 +318,11 @@ func (ui *UIManager) GetGameEngine() *game.GameEngine {
 	return ui.gameEngine
 }
 
+// SetCommandHistory sets the history recalled in the dashboard command input
+func (ui *UIManager) SetCommandHistory(history *game.CommandHistory) {
+	ui.dashboard.SetHistory(history)
+}
+
 // DisplayInterface implementation for game engine compatibility
 
 // ShowHelp displays help with the given commands (DisplayInterface method)